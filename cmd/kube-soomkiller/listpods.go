@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rophy/kube-soomkiller/internal/cgroup"
+	"github.com/rophy/kube-soomkiller/internal/controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// podCgroupUsage is the swap read from one pod's container cgroups
+type podCgroupUsage struct {
+	containers  int
+	swapBytes   int64
+	swapPercent float64 // max across containers
+	errors      int     // containers whose metrics could not be read
+}
+
+// runListPods implements "kube-soomkiller list-pods [flags]": it syncs the
+// node-scoped pod informer once, matches each cached pod to its container
+// cgroups by UID, prints what it found and exits. It is read-only, and
+// surfaces pods soomkiller can't see or can't read swap for, which would
+// otherwise only show up as pods that are never killed. Returns the process
+// exit code.
+func runListPods(args []string) int {
+	fs := flag.NewFlagSet("list-pods", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s list-pods [flags]\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Lists the pods the informer sees on this node, whether each has container cgroups, and its")
+		fmt.Fprintln(fs.Output(), "current swap usage. Cgroups with no matching pod are listed last.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+
+	var (
+		kubeconfig      string
+		kubeContext     string
+		nodeName        string
+		syncTimeout     time.Duration
+		cgroupRoot      string
+		procPath        string
+		runtimePrefixes string
+		kubepodsPath    string
+		excludeCgroups  string
+		unlimitedBasis  string
+	)
+	fs.StringVar(&kubeconfig, "kubeconfig", "", "Path to kubeconfig file (uses in-cluster config if not set)")
+	fs.StringVar(&kubeContext, "context", "", "Kubeconfig context to use (defaults to the current context; requires --kubeconfig)")
+	fs.StringVar(&nodeName, "node-name", os.Getenv("NODE_NAME"), "Name of the node to list pods for")
+	fs.DurationVar(&syncTimeout, "sync-timeout", 30*time.Second, "Give up if the pod informer hasn't synced within this long")
+	fs.StringVar(&cgroupRoot, "cgroup-root", "/sys/fs/cgroup", "Path to cgroup v2 root")
+	fs.StringVar(&procPath, "proc-path", cgroup.DefaultProcPath, "Path to procfs (e.g. /host/proc when the host's proc is mounted into the container)")
+	fs.StringVar(&runtimePrefixes, "runtime-prefixes", strings.Join(cgroup.DefaultRuntimePrefixes, ","), "Comma-separated list of container scope prefixes identifying container cgroups")
+	fs.StringVar(&kubepodsPath, "kubepods-path", cgroup.DefaultKubepodsPath, "Kubelet's pod cgroup parent relative to --cgroup-root")
+	fs.StringVar(&excludeCgroups, "exclude-cgroups", strings.Join(cgroup.DefaultExcludePaths, ","), "Comma-separated cgroup path substrings, relative to --kubepods-path, that discovery skips (empty excludes nothing)")
+	fs.StringVar(&unlimitedBasis, "unlimited-basis", cgroup.UnlimitedBasisSentinel, "Swap percent denominator for containers with no memory limit: node-memory or sentinel")
+	klog.InitFlags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	if nodeName == "" {
+		fmt.Fprintln(os.Stderr, "--node-name or NODE_NAME environment variable is required")
+		return 2
+	}
+	if kubeContext != "" && kubeconfig == "" {
+		fmt.Fprintln(os.Stderr, "--context requires --kubeconfig")
+		return 2
+	}
+	if unlimitedBasis != cgroup.UnlimitedBasisSentinel && unlimitedBasis != cgroup.UnlimitedBasisNodeMemory {
+		fmt.Fprintf(os.Stderr, "--unlimited-basis must be %q or %q, got %q\n", cgroup.UnlimitedBasisSentinel, cgroup.UnlimitedBasisNodeMemory, unlimitedBasis)
+		return 2
+	}
+
+	_, watchClient, err := createK8sClients(kubeconfig, kubeContext, 20, 30, 30*time.Second)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create Kubernetes client: %v\n", err)
+		return 1
+	}
+
+	// No resync and all phases: this lists once and exits
+	podInformer := controller.NewPodInformer(watchClient, nodeName, 0, false)
+	ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+	defer cancel()
+	go podInformer.Run(ctx.Done())
+	if !podInformer.WaitForCacheSync(ctx.Done()) {
+		fmt.Fprintf(os.Stderr, "pod informer did not sync within %s\n", syncTimeout)
+		return 1
+	}
+
+	scanner := cgroup.NewScannerWithOptions(cgroupRoot, cgroup.Options{
+		RuntimePrefixes: splitList(runtimePrefixes),
+		ProcPath:        procPath,
+		UnlimitedBasis:  unlimitedBasis,
+		KubepodsPath:    kubepodsPath,
+		ExcludePaths:    excludeList(excludeCgroups),
+	})
+	cgroups, err := scanner.FindPodCgroups()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to find pod cgroups: %v\n", err)
+		return 1
+	}
+
+	usage := make(map[string]*podCgroupUsage)
+	for _, cgroupPath := range cgroups.Cgroups {
+		uid := cgroup.ExtractPodUID(cgroupPath)
+		if uid == "" {
+			continue
+		}
+		u, ok := usage[uid]
+		if !ok {
+			u = &podCgroupUsage{}
+			usage[uid] = u
+		}
+		u.containers++
+		containerMetrics, err := scanner.GetContainerMetrics(cgroupPath)
+		if err != nil {
+			klog.V(2).InfoS("Failed to get metrics for cgroup", "cgroupPath", cgroupPath, "err", err)
+			u.errors++
+			continue
+		}
+		u.swapBytes += containerMetrics.SwapCurrent
+		if swapPercent := containerMetrics.SwapPercent(); swapPercent > u.swapPercent {
+			u.swapPercent = swapPercent
+		}
+	}
+
+	pods := podInformer.ListPods()
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+
+	out := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(out, "POD\tUID\tQOS\tPHASE\tCGROUPS\tSWAP\tSWAP%\tSTATUS")
+	for _, pod := range pods {
+		// Cgroup paths carry the UID with underscores; ExtractPodUID turns them all into dashes
+		key := strings.ReplaceAll(string(pod.UID), "_", "-")
+		u := usage[key]
+		delete(usage, key)
+		fmt.Fprintf(out, "%s/%s\t%s\t%s\t%s\t%s\n", pod.Namespace, pod.Name, pod.UID, pod.Status.QOSClass, pod.Status.Phase, usageColumns(pod, u))
+	}
+	// Whatever is left has cgroups but no pod in the cache: a deleted pod
+	// whose cgroup lingers, or a UID the informer doesn't know
+	orphans := make([]string, 0, len(usage))
+	for uid := range usage {
+		orphans = append(orphans, uid)
+	}
+	sort.Strings(orphans)
+	for _, uid := range orphans {
+		u := usage[uid]
+		fmt.Fprintf(out, "-\t%s\t-\t-\t%d\t%d\t%.1f\tno pod in informer cache\n", uid, u.containers, u.swapBytes, u.swapPercent)
+	}
+	out.Flush()
+
+	if len(cgroups.Unrecognized) > 0 {
+		fmt.Fprintf(os.Stderr, "%d container scopes matched no --runtime-prefixes entry and were ignored\n", len(cgroups.Unrecognized))
+	}
+	return 0
+}
+
+// usageColumns formats the CGROUPS, SWAP, SWAP% and STATUS columns for pod
+func usageColumns(pod *corev1.Pod, u *podCgroupUsage) string {
+	if u == nil {
+		status := "no cgroup found"
+		if pod.Status.Phase != corev1.PodRunning {
+			status = "no cgroup found (pod not running)"
+		}
+		return "0\t-\t-\t" + status
+	}
+
+	status := "ok"
+	switch {
+	case u.errors == u.containers:
+		status = "swap unreadable"
+	case u.errors > 0:
+		status = fmt.Sprintf("swap unreadable for %d of %d containers", u.errors, u.containers)
+	case pod.Status.QOSClass != corev1.PodQOSBurstable:
+		status = "ok (not burstable, never a candidate)"
+	}
+	return fmt.Sprintf("%d\t%d\t%.1f\t%s", u.containers, u.swapBytes, u.swapPercent, status)
+}
@@ -2,20 +2,29 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"net/http"
+	"net/http/pprof"
 	"os"
 	"os/signal"
+	"runtime"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rophy/kube-soomkiller/internal/audit"
 	"github.com/rophy/kube-soomkiller/internal/cgroup"
 	"github.com/rophy/kube-soomkiller/internal/controller"
+	"github.com/rophy/kube-soomkiller/internal/lease"
 	"github.com/rophy/kube-soomkiller/internal/metrics"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -28,27 +37,166 @@ import (
 var version = "dev"
 
 func main() {
+	// Offline threshold tuning against captured snapshots; see runReplay
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		os.Exit(runReplay(os.Args[2:]))
+	}
+	// Read-only check of what the informer and scanner see; see runListPods
+	if len(os.Args) > 1 && os.Args[1] == "list-pods" {
+		os.Exit(runListPods(os.Args[2:]))
+	}
+
 	var (
-		kubeconfig            string
-		nodeName              string
-		pollInterval          time.Duration
-		swapThresholdPercent  float64
-		cgroupRoot            string
-		dryRun                bool
-		metricsAddr           string
-		protectedNamespaces   string
-		showVersion           bool
+		kubeconfig           string
+		kubeContext          string
+		kubeAPIQPS           float64
+		kubeAPIBurst         int
+		kubeAPITimeout       time.Duration
+		informerResync       time.Duration
+		informerRunningOnly  bool
+		nodeName             string
+		pollInterval         time.Duration
+		shutdownTimeout      time.Duration
+		scanTimeout          time.Duration
+		startupGracePeriod   time.Duration
+		pauseRefresh         time.Duration
+		swapThresholdPercent float64
+		swapWarnPercent      float64
+		warnInterval         time.Duration
+		swapGrowthThreshold  float64
+		killTopN             int
+		maxCandidates        int
+		namespaceKillBudget  string
+		killMode             string
+		pidsThreshold        float64
+		nodeFraction         float64
+		killOnSwapLimit      bool
+		killOnMemoryHigh     bool
+		scanOnlyOnSwapIO     bool
+		swapIOCounters       string
+		deferOnReclaim       bool
+		reclaimThreshold     float64
+		adaptivePoll         bool
+		minPollInterval      time.Duration
+		taintOnPressure      bool
+		nodeSwapActivation   float64
+		taintAfter           time.Duration
+		emergencySwapPercent float64
+		emergencyGracePeriod time.Duration
+		escalationDelay      time.Duration
+		cgroupRoot           string
+		kubepodsPath         string
+		excludeCgroups       string
+		procPath             string
+		unlimitedBasis       string
+		dryRun               bool
+		metricsAddr          string
+		enablePprof          bool
+		pprofAddr            string
+		metricsPath          string
+		metricsTLSCert       string
+		metricsTLSKey        string
+		metricsClientCA      string
+		protectedNamespaces  string
+		protectSelector      string
+		dryRunNamespaces     string
+		skipUnmanaged        bool
+		skipOwnerKinds       string
+		protectDaemonSetPods bool
+		excludePodConditions string
+		excludeNotReadyAfter time.Duration
+		annotateOwner        bool
+		ignoreContainers     string
+		runtimePrefixes      string
+		scoreWeights         string
+		scoreExpr            string
+		sortBy               string
+		discovery            string
+		discoveryResync      time.Duration
+		auditLogPath         string
+		eventComponent       string
+		eventReason          string
+		enableLease          bool
+		leaseDuration        time.Duration
+		leaseNamespace       string
+		once                 bool
+		showVersion          bool
 	)
 
 	flag.BoolVar(&showVersion, "version", false, "Print version and exit")
+	flag.BoolVar(&enableLease, "enable-lease", false, "Hold a per-node coordination.k8s.io Lease so only one controller acts on each node")
+	flag.DurationVar(&leaseDuration, "lease-duration", 15*time.Second, "Duration of the per-node lease; it is renewed every third of this")
+	flag.StringVar(&leaseNamespace, "lease-namespace", getEnvString("POD_NAMESPACE", "kube-soomkiller"), "Namespace for the per-node lease (also via POD_NAMESPACE env var)")
+	flag.BoolVar(&once, "once", false, "Run a single reconcile pass after informer sync and exit (dry-run unless --dry-run=false is given)")
 	flag.StringVar(&kubeconfig, "kubeconfig", "", "Path to kubeconfig file (uses in-cluster config if not set)")
+	flag.StringVar(&kubeContext, "context", "", "Kubeconfig context to use (defaults to the current context; requires --kubeconfig)")
+	flag.Float64Var(&kubeAPIQPS, "kube-api-qps", 20, "Maximum sustained queries per second to the Kubernetes API")
+	flag.IntVar(&kubeAPIBurst, "kube-api-burst", 30, "Maximum burst of queries to the Kubernetes API")
+	flag.DurationVar(&kubeAPITimeout, "kube-api-timeout", 30*time.Second, "Timeout for individual Kubernetes API requests such as pod deletes (0 means no timeout; informer watches are exempt)")
+	flag.DurationVar(&informerResync, "informer-resync", 30*time.Second, "How often the pod informer replays its cache to event handlers (0 disables resync)")
+	flag.BoolVar(&informerRunningOnly, "informer-running-only", false, "Cache only pods in phase Running, leaving completed (and pending) pods out of the informer")
 	flag.StringVar(&nodeName, "node-name", os.Getenv("NODE_NAME"), "Name of the node to monitor")
 	flag.DurationVar(&pollInterval, "poll-interval", 1*time.Second, "How often to sample /proc/vmstat (minimum 1s)")
+	flag.DurationVar(&shutdownTimeout, "shutdown-timeout", 10*time.Second, "Max time to wait for an in-flight reconcile to finish on shutdown")
+	flag.DurationVar(&scanTimeout, "scan-timeout", 5*time.Second, "Abandon a cgroup scan that takes longer than this and skip the reconcile; later polls are skipped until the abandoned scan returns (0 disables)")
+	flag.DurationVar(&startupGracePeriod, "startup-grace-period", 0, "After startup, scan and report but never delete pods for this long (0 disables)")
+	flag.DurationVar(&pauseRefresh, "pause-refresh", 30*time.Second, "How often to re-read the node's "+controller.PauseAnnotation+" annotation; while it is \"true\" pods are never deleted (0 disables the check)")
 	flag.Float64Var(&swapThresholdPercent, "swap-threshold-percent", 1.0, "Kill pods with swap usage > this % of memory limit")
+	flag.Float64Var(&swapWarnPercent, "swap-warn-threshold-percent", 0, "Emit a SoomkillWarning event for pods with swap usage > this % of memory limit but below the kill threshold (0 disables)")
+	flag.DurationVar(&warnInterval, "warn-interval", 10*time.Minute, "Minimum time between SoomkillWarning events for the same pod")
+	flag.Float64Var(&swapGrowthThreshold, "swap-growth-threshold-bytes-per-sec", 0, "Also kill pods whose swap usage grows faster than this many bytes/sec (0 disables)")
+	flag.BoolVar(&killOnSwapLimit, "kill-on-swap-limit-events", false, "Also kill pods whose memory.swap.events max/fail counters increased since the previous poll")
+	flag.Float64Var(&pidsThreshold, "pids-threshold-percent", 0, "Also kill swapping pods whose pids.current exceeds this % of pids.max (0 disables)")
+	flag.Float64Var(&nodeFraction, "swap-node-fraction-threshold", 0, "Also kill pods whose swap bytes exceed this fraction (0-1) of node SwapTotal (0 disables)")
+	flag.StringVar(&killMode, "kill-mode", controller.KillModeDelete, "How pods are killed: delete, or evict (honors PodDisruptionBudgets)")
+	flag.BoolVar(&killOnMemoryHigh, "kill-on-memory-high-events", false, "Also kill swapping pods whose memory.events high counter increased since the previous poll")
+	flag.IntVar(&killTopN, "kill-top-n", 0, "Delete at most this many pods per poll, highest score first (0 = unlimited)")
+	flag.IntVar(&maxCandidates, "max-candidates", 0, "Keep at most this many swapping pods per scan, highest swap first, to bound memory on nodes with thousands of cgroups (0 = unlimited)")
+	flag.StringVar(&namespaceKillBudget, "namespace-kill-budget", "", "Comma-separated namespace=count pairs limiting kills per namespace per minute (e.g. batch=2,web=5); unlisted namespaces are unlimited")
+	flag.BoolVar(&adaptivePoll, "adaptive-poll", false, "Shrink the poll interval toward --min-poll-interval while swap pressure rises, relaxing back to --poll-interval when calm")
+	flag.DurationVar(&minPollInterval, "min-poll-interval", 100*time.Millisecond, "Fastest poll interval used by --adaptive-poll")
+	flag.StringVar(&swapIOCounters, "swapio-counters", strings.Join(controller.DefaultSwapIOCounters, ","), "Comma-separated /proc/vmstat counters summed into the node swap I/O rate; names ending in \"in\" count as swap-in, the rest as swap-out (e.g. pswpin,pswpout,zswpout for zswap)")
+	flag.BoolVar(&deferOnReclaim, "defer-on-active-reclaim", false, "Hold kills for one poll when the node's reclaim rate (pgsteal_kswapd + pgsteal_direct in /proc/vmstat) exceeds --reclaim-threshold-pages-per-sec, giving the kernel a chance to free memory first")
+	flag.Float64Var(&reclaimThreshold, "reclaim-threshold-pages-per-sec", controller.DefaultReclaimThreshold, "Pages reclaimed per second above which --defer-on-active-reclaim considers reclaim active")
+	flag.BoolVar(&scanOnlyOnSwapIO, "scan-only-on-swapio", false, "Skip the cgroup scan when /proc/vmstat shows no swap I/O since the previous poll and no pod was over threshold")
+	flag.BoolVar(&taintOnPressure, "taint-on-pressure", false, "Add a NoSchedule taint to the node while node swap usage stays above --node-swap-activation-percent (requires nodes update RBAC)")
+	flag.Float64Var(&nodeSwapActivation, "node-swap-activation-percent", 80, "Node swap used % (of total swap) considered sustained pressure for --taint-on-pressure")
+	flag.DurationVar(&taintAfter, "taint-after", 5*time.Minute, "How long node swap must stay above --node-swap-activation-percent before the node is tainted")
+	flag.Float64Var(&emergencySwapPercent, "emergency-swap-percent", 0, "Node swap used % (of total swap in /proc/swaps) above which --emergency-grace-period caps pod grace periods (0 disables)")
+	flag.DurationVar(&emergencyGracePeriod, "emergency-grace-period", 5*time.Second, "Grace period for kills while node swap is above --emergency-swap-percent (pods with a shorter one keep theirs)")
+	flag.DurationVar(&escalationDelay, "escalation-delay", 0, "Emit a SoomkillEscalation warning event when a pod first goes over threshold and only kill it if still over threshold after this long (0 kills immediately)")
 	flag.StringVar(&cgroupRoot, "cgroup-root", "/sys/fs/cgroup", "Path to cgroup v2 root")
+	flag.StringVar(&kubepodsPath, "kubepods-path", cgroup.DefaultKubepodsPath, "Kubelet's pod cgroup parent relative to --cgroup-root (e.g. kubelet.slice/kubelet-kubepods.slice for a custom kubelet --cgroup-root)")
+	flag.StringVar(&excludeCgroups, "exclude-cgroups", strings.Join(cgroup.DefaultExcludePaths, ","), "Comma-separated cgroup path substrings, relative to --kubepods-path, that discovery skips (empty excludes nothing)")
+	flag.StringVar(&procPath, "proc-path", cgroup.DefaultProcPath, "Path to procfs (e.g. /host/proc when the host's proc is mounted into the container)")
+	flag.StringVar(&unlimitedBasis, "unlimited-basis", cgroup.UnlimitedBasisSentinel, "Swap percent denominator for containers with no memory limit: node-memory (node MemTotal, ahead of memory.swap.max) or sentinel (memory.swap.max if finite, else reads ~0%)")
 	flag.BoolVar(&dryRun, "dry-run", getEnvBool("DRY_RUN", true), "Log actions without executing")
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "Address to serve Prometheus metrics on")
+	flag.BoolVar(&enablePprof, "enable-pprof", false, "Serve net/http/pprof profiling handlers under /debug/pprof/ (exposes internals; keep the port private)")
+	flag.StringVar(&pprofAddr, "pprof-addr", "", "Serve --enable-pprof handlers on this separate plain-HTTP address (e.g. localhost:6060) instead of the metrics server")
+	flag.StringVar(&metricsPath, "metrics-path", "/metrics", "HTTP path to serve Prometheus metrics on")
+	flag.StringVar(&metricsTLSCert, "metrics-tls-cert", "", "TLS certificate file for the metrics server (serves HTTPS when set with --metrics-tls-key)")
+	flag.StringVar(&metricsTLSKey, "metrics-tls-key", "", "TLS private key file for the metrics server")
+	flag.StringVar(&metricsClientCA, "metrics-client-ca", "", "CA bundle for verifying metrics client certificates; when set, clients must present a certificate (requires --metrics-tls-cert)")
 	flag.StringVar(&protectedNamespaces, "protected-namespaces", "kube-system", "Comma-separated list of namespaces to never kill pods from")
+	flag.StringVar(&protectSelector, "protect-selector", "", "Label selector (e.g. critical=true) for pods to never kill, in addition to --protected-namespaces")
+	flag.StringVar(&dryRunNamespaces, "dry-run-namespaces", "", "Comma-separated list of namespaces where kills are only logged, even with --dry-run=false")
+	flag.BoolVar(&skipUnmanaged, "skip-unmanaged", false, "Never kill pods without a controller owner (standalone pods are not recreated)")
+	flag.StringVar(&skipOwnerKinds, "skip-owner-kinds", "", "Comma-separated list of owner kinds whose pods are never killed (e.g. Job)")
+	flag.BoolVar(&protectDaemonSetPods, "protect-daemonset-pods", true, "Never kill DaemonSet pods (they are immediately rescheduled onto the same node)")
+	flag.StringVar(&excludePodConditions, "exclude-pod-conditions", "", "Comma-separated list of pod condition types (e.g. DisruptionTarget) whose pods are never killed while the condition is True")
+	flag.DurationVar(&excludeNotReadyAfter, "exclude-not-ready-after", 0, "Never kill pods whose Ready condition has been False at least this long (0 disables)")
+	flag.StringVar(&ignoreContainers, "ignore-containers", "", "Comma-separated list of container names excluded from per-pod swap aggregation (e.g. logging sidecars)")
+	flag.BoolVar(&annotateOwner, "annotate-owner", false, "Before deleting a pod, annotate its ReplicaSet/StatefulSet with the kill time and swap percent (requires patch RBAC)")
+	flag.StringVar(&runtimePrefixes, "runtime-prefixes", strings.Join(cgroup.DefaultRuntimePrefixes, ","), "Comma-separated list of container scope prefixes identifying container cgroups")
+	flag.StringVar(&scoreWeights, "score-weights", "swap=1,psi=0", "Kill ordering weights as name=value pairs (signals: swap, psi)")
+	flag.StringVar(&scoreExpr, "score-expr", "", "Kill ordering expression over swap_percent, psi_full_avg10, swap_bytes and mem_bytes, largest first (replaces --score-weights; empty uses --score-weights)")
+	flag.StringVar(&sortBy, "sort-by", controller.SortByScore, "Kill ordering: score (--score-weights) or footprint (memory + swap bytes, largest first)")
+	flag.StringVar(&discovery, "discovery", cgroup.DiscoveryWalk, "Container cgroup discovery mode: walk (full walk every scan) or watch (fsnotify-maintained cache)")
+	flag.StringVar(&eventComponent, "event-component", "kube-soomkiller", "Component (reportingComponent) set on emitted Kubernetes events")
+	flag.StringVar(&eventReason, "event-reason", controller.DefaultEventReason, "Reason set on events for killed pods (single CamelCase token)")
+	flag.StringVar(&auditLogPath, "audit-log-path", "", "If set, append one JSON line per kill decision to this file")
+	flag.DurationVar(&discoveryResync, "discovery-resync-period", 1*time.Minute, "How often watch discovery re-walks cgroups to reconcile missed events")
 
 	klog.InitFlags(nil)
 	flag.Parse()
@@ -58,24 +206,171 @@ func main() {
 		os.Exit(0)
 	}
 
+	// --once is a diagnostic; only kill if the operator explicitly asked to
+	if once && !flagSet("dry-run") {
+		dryRun = true
+	}
+
 	// Validate required parameters
 	if nodeName == "" {
 		klog.Fatal("--node-name or NODE_NAME environment variable is required")
 	}
 
+	if kubeContext != "" && kubeconfig == "" {
+		klog.Fatal("--context requires --kubeconfig")
+	}
+
 	// Validate configuration parameters
+	if enableLease && leaseDuration < 3*time.Second {
+		klog.Fatalf("--lease-duration must be at least 3s, got %s", leaseDuration)
+	}
+	if kubeAPIQPS <= 0 {
+		klog.Fatalf("--kube-api-qps must be > 0, got %f", kubeAPIQPS)
+	}
+	if kubeAPIBurst < 1 {
+		klog.Fatalf("--kube-api-burst must be >= 1, got %d", kubeAPIBurst)
+	}
+	if kubeAPITimeout < 0 {
+		klog.Fatalf("--kube-api-timeout must be >= 0, got %s", kubeAPITimeout)
+	}
+	if informerResync < 0 {
+		klog.Fatalf("--informer-resync must be >= 0, got %s", informerResync)
+	}
 	if pollInterval < time.Second {
 		klog.Fatalf("--poll-interval must be at least 1s, got %s", pollInterval)
 	}
+	if adaptivePoll && (minPollInterval <= 0 || minPollInterval > pollInterval) {
+		klog.Fatalf("--min-poll-interval must be > 0 and <= --poll-interval (%s), got %s", pollInterval, minPollInterval)
+	}
 	if swapThresholdPercent < 0 {
 		klog.Fatalf("--swap-threshold-percent must be >= 0, got %f", swapThresholdPercent)
 	}
+	if swapWarnPercent < 0 || (swapWarnPercent > 0 && swapWarnPercent >= swapThresholdPercent) {
+		klog.Fatalf("--swap-warn-threshold-percent must be 0 or between 0 and --swap-threshold-percent (%f), got %f", swapThresholdPercent, swapWarnPercent)
+	}
+	if warnInterval < 0 {
+		klog.Fatalf("--warn-interval must be >= 0, got %s", warnInterval)
+	}
+	if escalationDelay < 0 {
+		klog.Fatalf("--escalation-delay must be >= 0, got %s", escalationDelay)
+	}
+	if excludeNotReadyAfter < 0 {
+		klog.Fatalf("--exclude-not-ready-after must be >= 0, got %s", excludeNotReadyAfter)
+	}
+	if pauseRefresh < 0 {
+		klog.Fatalf("--pause-refresh must be >= 0, got %s", pauseRefresh)
+	}
+	if startupGracePeriod < 0 {
+		klog.Fatalf("--startup-grace-period must be >= 0, got %s", startupGracePeriod)
+	}
+	if shutdownTimeout < 0 {
+		klog.Fatalf("--shutdown-timeout must be >= 0, got %s", shutdownTimeout)
+	}
+	if scanTimeout < 0 {
+		klog.Fatalf("--scan-timeout must be >= 0, got %s", scanTimeout)
+	}
+	if pidsThreshold < 0 || pidsThreshold > 100 {
+		klog.Fatalf("--pids-threshold-percent must be between 0 and 100, got %f", pidsThreshold)
+	}
+	if nodeFraction < 0 || nodeFraction > 1 {
+		klog.Fatalf("--swap-node-fraction-threshold must be between 0 and 1, got %f", nodeFraction)
+	}
+	if killTopN < 0 {
+		klog.Fatalf("--kill-top-n must be >= 0, got %d", killTopN)
+	}
+	if reclaimThreshold <= 0 {
+		klog.Fatalf("--reclaim-threshold-pages-per-sec must be > 0, got %f", reclaimThreshold)
+	}
+	if maxCandidates < 0 {
+		klog.Fatalf("--max-candidates must be >= 0, got %d", maxCandidates)
+	}
+	namespaceBudgets, err := controller.ParseNamespaceKillBudgets(namespaceKillBudget)
+	if err != nil {
+		klog.Fatalf("--namespace-kill-budget is invalid: %v", err)
+	}
+	if swapGrowthThreshold < 0 {
+		klog.Fatalf("--swap-growth-threshold-bytes-per-sec must be >= 0, got %f", swapGrowthThreshold)
+	}
+	if nodeSwapActivation < 0 || nodeSwapActivation > 100 {
+		klog.Fatalf("--node-swap-activation-percent must be between 0 and 100, got %f", nodeSwapActivation)
+	}
+	if emergencySwapPercent < 0 || emergencySwapPercent > 100 {
+		klog.Fatalf("--emergency-swap-percent must be between 0 and 100, got %f", emergencySwapPercent)
+	}
+	if emergencyGracePeriod < 0 {
+		klog.Fatalf("--emergency-grace-period must be >= 0, got %s", emergencyGracePeriod)
+	}
+	if taintAfter < 0 {
+		klog.Fatalf("--taint-after must be >= 0, got %s", taintAfter)
+	}
+	if !strings.HasPrefix(metricsPath, "/") {
+		klog.Fatalf("--metrics-path must start with /, got %q", metricsPath)
+	}
+	switch metricsPath {
+	case "/", "/healthz", "/readyz", "/candidates", "/debug/mapping":
+		klog.Fatalf("--metrics-path must not shadow a built-in endpoint, got %q", metricsPath)
+	}
+	if (metricsTLSCert == "") != (metricsTLSKey == "") {
+		klog.Fatal("--metrics-tls-cert and --metrics-tls-key must be set together")
+	}
+	if metricsClientCA != "" && metricsTLSCert == "" {
+		klog.Fatal("--metrics-client-ca requires --metrics-tls-cert and --metrics-tls-key")
+	}
+	metricsTLSConfig, err := loadMetricsTLSConfig(metricsClientCA)
+	if err != nil {
+		klog.Fatalf("--metrics-client-ca is invalid: %v", err)
+	}
+	if eventComponent == "" {
+		klog.Fatal("--event-component must not be empty")
+	}
+	if err := controller.ValidateEventReason(eventReason); err != nil {
+		klog.Fatalf("--event-reason is invalid: %v", err)
+	}
+	weights, err := controller.ParseScoreWeights(scoreWeights)
+	if err != nil {
+		klog.Fatalf("--score-weights is invalid: %v", err)
+	}
+	if sortBy != controller.SortByScore && sortBy != controller.SortByFootprint {
+		klog.Fatalf("--sort-by must be %q or %q, got %q", controller.SortByScore, controller.SortByFootprint, sortBy)
+	}
+	var expr *controller.ScoreExpr
+	if scoreExpr != "" {
+		if flagSet("score-weights") || sortBy == controller.SortByFootprint {
+			klog.Fatal("--score-expr cannot be combined with --score-weights or --sort-by=footprint")
+		}
+		if expr, err = controller.ParseScoreExpr(scoreExpr); err != nil {
+			klog.Fatalf("--score-expr is invalid: %v", err)
+		}
+	}
+	// An empty selector would match every pod, so it means "none" here
+	var protectLabels labels.Selector
+	if strings.TrimSpace(protectSelector) != "" {
+		protectLabels, err = labels.Parse(protectSelector)
+		if err != nil {
+			klog.Fatalf("--protect-selector is invalid: %v", err)
+		}
+	}
+	if unlimitedBasis != cgroup.UnlimitedBasisSentinel && unlimitedBasis != cgroup.UnlimitedBasisNodeMemory {
+		klog.Fatalf("--unlimited-basis must be %q or %q, got %q", cgroup.UnlimitedBasisSentinel, cgroup.UnlimitedBasisNodeMemory, unlimitedBasis)
+	}
+	if discovery != cgroup.DiscoveryWalk && discovery != cgroup.DiscoveryWatch {
+		klog.Fatalf("--discovery must be %q or %q, got %q", cgroup.DiscoveryWalk, cgroup.DiscoveryWatch, discovery)
+	}
+	if discovery == cgroup.DiscoveryWatch && discoveryResync <= 0 {
+		klog.Fatalf("--discovery-resync-period must be > 0, got %s", discoveryResync)
+	}
 
 	klog.InfoS("Starting kube-soomkiller", "node", nodeName, "version", version)
 	klog.InfoS("Configuration loaded", "pollInterval", pollInterval, "swapThresholdPercent", swapThresholdPercent, "dryRun", dryRun)
 
 	// Create cgroup scanner
-	cgroupScanner := cgroup.NewScanner(cgroupRoot)
+	cgroupScanner := cgroup.NewScannerWithOptions(cgroupRoot, cgroup.Options{
+		RuntimePrefixes: splitList(runtimePrefixes),
+		ProcPath:        procPath,
+		UnlimitedBasis:  unlimitedBasis,
+		KubepodsPath:    kubepodsPath,
+		ExcludePaths:    excludeList(excludeCgroups),
+	})
 
 	// Validate environment (cgroup v2, systemd, swap enabled)
 	if err := cgroupScanner.ValidateEnvironment(); err != nil {
@@ -83,10 +378,64 @@ func main() {
 	}
 	klog.InfoS("Environment validated", "cgroupVersion", "v2", "cgroupDriver", "systemd", "swapEnabled", true)
 
+	// Detect the runtimes once so the first one's prefix is matched first and "all pods unrecognized" is easy to debug
+	containerRuntimes := cgroupScanner.DetectRuntimes()
+	klog.InfoS("Detected container runtime", "runtimes", containerRuntimes, "runtimePrefixes", runtimePrefixes)
+	if len(containerRuntimes) > 1 {
+		klog.InfoS("Multiple container runtimes on node, matching scopes from all of them", "runtimes", containerRuntimes)
+	}
+
+	// Warn when cgroups allow swap but the node has no swap device to back it
+	if devices, err := cgroupScanner.GetSwapDevices(); err != nil {
+		klog.ErrorS(err, "Could not read swap devices")
+	} else if len(devices) == 0 {
+		klog.Warning("No active swap devices found in /proc/swaps, pods cannot swap and will be OOM-killed instead")
+	} else {
+		for _, dev := range devices {
+			klog.InfoS("Swap device found", "device", dev.Name, "type", dev.Type, "sizeBytes", dev.Size)
+		}
+	}
+
+	// A counter the kernel doesn't report reads as zero forever, hiding swap I/O
+	if counters, err := cgroupScanner.GetVmstatCounters(splitList(swapIOCounters)); err == nil {
+		for _, name := range splitList(swapIOCounters) {
+			if _, ok := counters[name]; !ok {
+				klog.Warningf("Swap I/O counter %q not found in /proc/vmstat", name)
+			}
+		}
+	}
+
+	// Handle shutdown gracefully
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		sig := <-sigCh
+		klog.InfoS("Received signal, shutting down", "signal", sig)
+		cancel()
+	}()
+
+	// Switch to watch-based discovery before anything else starts scanning
+	if discovery == cgroup.DiscoveryWatch {
+		if err := cgroupScanner.StartWatch(ctx, discoveryResync); err != nil {
+			klog.Fatalf("Failed to start cgroup watch: %v", err)
+		}
+		klog.InfoS("Cgroup watch discovery enabled", "resyncPeriod", discoveryResync)
+	}
+
 	// Register Prometheus metrics (with node label)
 	m := metrics.NewMetrics(nodeName)
 	m.Register()
+	for _, name := range containerRuntimes {
+		m.RuntimeInfo.WithLabelValues(name).Set(1)
+	}
 	metrics.RegisterSwapIOCollector(cgroupScanner, nodeName)
+	metrics.RegisterSwapDeviceCollector(cgroupScanner, nodeName)
+
+	m.BuildInfo.WithLabelValues(version, runtime.Version()).Set(1)
 
 	// Set config metrics
 	m.ConfigSwapThresholdPercent.Set(swapThresholdPercent)
@@ -96,76 +445,215 @@ func main() {
 		m.ConfigDryRun.Set(0)
 	}
 
-	// Start metrics server
-	go func() {
-		http.Handle("/metrics", promhttp.Handler())
-		http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("ok"))
-		})
-		klog.InfoS("Metrics server started", "addr", metricsAddr)
-		if err := http.ListenAndServe(metricsAddr, nil); err != nil {
-			klog.ErrorS(err, "Metrics server failed")
-		}
-	}()
-
 	// Create Kubernetes client
-	k8sClient, err := createK8sClient(kubeconfig)
+	k8sClient, watchClient, err := createK8sClients(kubeconfig, kubeContext, kubeAPIQPS, kubeAPIBurst, kubeAPITimeout)
 	if err != nil {
 		klog.Fatalf("Failed to create Kubernetes client: %v", err)
 	}
 
-	// Parse protected namespaces
-	var protectedNSList []string
-	if protectedNamespaces != "" {
-		for _, ns := range strings.Split(protectedNamespaces, ",") {
-			ns = strings.TrimSpace(ns)
-			if ns != "" {
-				protectedNSList = append(protectedNSList, ns)
-			}
+	killAction, err := controller.NewKillAction(killMode, k8sClient)
+	if err != nil {
+		klog.Fatalf("--kill-mode is invalid: %v", err)
+	}
+
+	// Guard against a second controller for this node (e.g. overlapping DaemonSet revisions).
+	// --once is an ad-hoc diagnostic run next to the live DaemonSet, which holds the lease.
+	var leaseDone chan struct{}
+	stopLease := func() {}
+	if enableLease && once {
+		klog.InfoS("Skipping node lease in --once mode", "dryRun", dryRun)
+	} else if enableLease {
+		identity, err := os.Hostname()
+		if err != nil {
+			klog.Fatalf("Failed to determine lease identity: %v", err)
+		}
+		lock := lease.New(k8sClient, leaseNamespace, lease.LeaseName(nodeName), identity, leaseDuration)
+		if err := lock.Acquire(ctx); err != nil {
+			klog.Fatalf("Failed to acquire node lease, another controller may be running for this node: %v", err)
 		}
+		klog.InfoS("Acquired node lease", "lease", klog.KRef(leaseNamespace, lease.LeaseName(nodeName)), "identity", identity)
+
+		// Held until the controller has fully stopped, then released
+		var leaseCtx context.Context
+		leaseCtx, stopLease = context.WithCancel(context.Background())
+		leaseDone = make(chan struct{})
+		go func() {
+			defer close(leaseDone)
+			lock.Hold(leaseCtx, cancel)
+		}()
 	}
+	defer func() {
+		stopLease()
+		if leaseDone != nil {
+			<-leaseDone
+		}
+	}()
+
+	// Parse protected namespaces
+	protectedNSList := splitList(protectedNamespaces)
 
 	// Create event recorder for emitting Kubernetes events
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{
 		Interface: k8sClient.CoreV1().Events(""),
 	})
+	defer eventBroadcaster.Shutdown()
 	eventRecorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{
-		Component: "kube-soomkiller",
+		Component: eventComponent,
 	})
 
+	// Open audit log for durable per-decision records
+	var auditLog *audit.Logger
+	if auditLogPath != "" {
+		auditLog, err = audit.Open(auditLogPath)
+		if err != nil {
+			klog.Fatalf("Failed to open audit log: %v", err)
+		}
+		defer auditLog.Close()
+		klog.InfoS("Audit log enabled", "path", auditLogPath)
+	}
+
+	// A dropped field selector would make the informer cluster-wide and kills could hit other nodes
+	if err := controller.VerifyNodeSelector(ctx, k8sClient, nodeName); err != nil {
+		klog.ErrorS(err, "Pod node selector check failed, the pod cache may include pods from other nodes and kills could target them", "node", nodeName)
+	} else {
+		klog.InfoS("Verified pod node selector", "node", nodeName)
+	}
+
 	// Create node-scoped pod informer
-	podInformer := controller.NewPodInformer(k8sClient, nodeName, 30*time.Second)
+	podInformer := controller.NewPodInformer(watchClient, nodeName, informerResync, informerRunningOnly)
 
 	// Register per-container metrics collector (uses informer for pod lookup)
-	metrics.RegisterContainerMetricsCollector(cgroupScanner, podInformer, nodeName)
+	containerCollector := metrics.RegisterContainerMetricsCollector(cgroupScanner, podInformer, nodeName, swapThresholdPercent)
 
 	// Create controller
 	ctrl := controller.New(controller.Config{
-		NodeName:             nodeName,
-		PollInterval:         pollInterval,
-		SwapThresholdPercent: swapThresholdPercent,
-		DryRun:               dryRun,
-		ProtectedNamespaces:  protectedNSList,
-		K8sClient:            k8sClient,
-		CgroupScanner:        cgroupScanner,
-		EventRecorder:        eventRecorder,
-		PodInformer:          podInformer,
+		NodeName:              nodeName,
+		PollInterval:          pollInterval,
+		ShutdownTimeout:       shutdownTimeout,
+		ScanTimeout:           scanTimeout,
+		StartupGracePeriod:    startupGracePeriod,
+		PauseRefresh:          pauseRefresh,
+		SwapThresholdPercent:  swapThresholdPercent,
+		WarnThresholdPercent:  swapWarnPercent,
+		WarnInterval:          warnInterval,
+		SwapGrowthThreshold:   swapGrowthThreshold,
+		KillOnSwapLimitEvents: killOnSwapLimit,
+		KillOnMemoryHigh:      killOnMemoryHigh,
+		KillTopN:              killTopN,
+		MaxCandidates:         maxCandidates,
+		NamespaceKillBudgets:  namespaceBudgets,
+		PidsThresholdPercent:  pidsThreshold,
+		NodeFractionThreshold: nodeFraction,
+		AdaptivePoll:          adaptivePoll,
+		MinPollInterval:       minPollInterval,
+		ScanOnlyOnSwapIO:      scanOnlyOnSwapIO,
+		DeferOnActiveReclaim:  deferOnReclaim,
+		ReclaimThreshold:      reclaimThreshold,
+		SwapIOCounters:        splitList(swapIOCounters),
+		TaintOnPressure:       taintOnPressure,
+		SwapActivationPercent: nodeSwapActivation,
+		TaintAfter:            taintAfter,
+		EmergencySwapPercent:  emergencySwapPercent,
+		EmergencyGracePeriod:  emergencyGracePeriod,
+		EscalationDelay:       escalationDelay,
+		ExcludeNotReadyAfter:  excludeNotReadyAfter,
+		DryRun:                dryRun,
+		DryRunNamespaces:      splitList(dryRunNamespaces),
+		ProtectedNamespaces:   protectedNSList,
+		SkipUnmanaged:         skipUnmanaged,
+		SkipOwnerKinds:        splitList(skipOwnerKinds),
+		ProtectDaemonSetPods:  protectDaemonSetPods,
+		ExcludeConditions:     splitList(excludePodConditions),
+		AnnotateOwner:         annotateOwner,
+		IgnoreContainers:      splitList(ignoreContainers),
+		K8sClient:             k8sClient,
+		KillAction:            killAction,
+		CgroupScanner:         cgroupScanner,
+		EventRecorder:         eventRecorder,
+		EventReason:           eventReason,
+		PodInformer:           podInformer,
+		Metrics:               m,
+		AuditLog:              auditLog,
+		ScoreWeights:          weights,
+		ScoreExpr:             expr,
+		SortBy:                sortBy,
+		ProtectSelector:       protectLabels,
 	})
 
-	// Handle shutdown gracefully
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	// Set once the pod informer cache has synced; until then pod UIDs can't be resolved
+	var informerSynced atomic.Bool
+
+	// Start metrics server (not in --once mode, where it would clash with the running daemon's port)
+	if !once {
+		go func() {
+			mux := http.NewServeMux()
+			mux.Handle(metricsPath, promhttp.Handler())
+			mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+				// Fail liveness if the reconcile loop is wedged (e.g. stuck reading a cgroup file)
+				if err := ctrl.CheckHealth(); err != nil {
+					http.Error(w, err.Error(), http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("ok"))
+			})
+			mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+				if !informerSynced.Load() {
+					http.Error(w, "pod informer cache not synced", http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("ok"))
+			})
+			mux.HandleFunc("/candidates", func(w http.ResponseWriter, r *http.Request) {
+				// Read-only view of what the controller sees right now (never kills)
+				candidates, err := ctrl.ListCandidates(r.Context())
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(candidates)
+			})
+			mux.HandleFunc("/debug/mapping", func(w http.ResponseWriter, r *http.Request) {
+				// How each container cgroup resolves to a pod/container, and where it fails
+				mapping, err := containerCollector.Mapping()
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(mapping)
+			})
+			if enablePprof && pprofAddr == "" {
+				registerPprof(mux)
+			}
+			server := &http.Server{Addr: metricsAddr, Handler: mux, TLSConfig: metricsTLSConfig}
+			klog.InfoS("Metrics server started", "addr", metricsAddr, "metricsPath", metricsPath, "tls", metricsTLSCert != "", "clientAuth", metricsClientCA != "")
+			var err error
+			if metricsTLSCert != "" {
+				err = server.ListenAndServeTLS(metricsTLSCert, metricsTLSKey)
+			} else {
+				err = server.ListenAndServe()
+			}
+			if err != nil {
+				klog.ErrorS(err, "Metrics server failed")
+			}
+		}()
+	}
 
-	go func() {
-		sig := <-sigCh
-		klog.InfoS("Received signal, shutting down", "signal", sig)
-		cancel()
-	}()
+	// Profiling on its own listener, e.g. bound to localhost and reached with kubectl port-forward
+	if enablePprof && pprofAddr != "" && !once {
+		go func() {
+			mux := http.NewServeMux()
+			registerPprof(mux)
+			klog.InfoS("Pprof server started", "addr", pprofAddr)
+			if err := http.ListenAndServe(pprofAddr, mux); err != nil {
+				klog.ErrorS(err, "Pprof server failed")
+			}
+		}()
+	}
 
 	// Start pod informer in background
 	go podInformer.Run(ctx.Done())
@@ -175,8 +663,16 @@ func main() {
 	if !podInformer.WaitForCacheSync(ctx.Done()) {
 		klog.Fatal("Failed to sync pod informer cache")
 	}
+	informerSynced.Store(true)
 	klog.InfoS("Pod informer cache synced")
 
+	if once {
+		if err := ctrl.RunOnce(ctx); err != nil {
+			klog.Fatalf("Reconcile failed: %v", err)
+		}
+		return
+	}
+
 	// Run controller
 	if err := ctrl.Run(ctx); err != nil {
 		klog.Fatalf("Controller error: %v", err)
@@ -185,20 +681,113 @@ func main() {
 	klog.InfoS("Controller stopped")
 }
 
-func createK8sClient(kubeconfig string) (*kubernetes.Clientset, error) {
+// createK8sClients returns a client for regular API calls, with the request
+// timeout applied, and a client for the pod informer. The informer's watches
+// are long-running, so they must not be cut off by an HTTP client timeout.
+func createK8sClients(kubeconfig, kubeContext string, qps float64, burst int, timeout time.Duration) (*kubernetes.Clientset, *kubernetes.Clientset, error) {
 	var config *rest.Config
 	var err error
 
 	if kubeconfig != "" {
-		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+		config, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig},
+			&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+		).ClientConfig()
 	} else {
 		config, err = rest.InClusterConfig()
 	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Raise the client-side rate limit so kills aren't throttled during incidents
+	config.QPS = float32(qps)
+	config.Burst = burst
+
+	watchClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config = rest.CopyConfig(config)
+	config.Timeout = timeout
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return client, watchClient, nil
+}
+
+// loadMetricsTLSConfig returns the metrics server TLS config requiring
+// client certificates signed by the CA bundle at caFile, or nil if caFile is
+// empty (the server certificate itself is loaded by ListenAndServeTLS)
+func loadMetricsTLSConfig(caFile string) (*tls.Config, error) {
+	if caFile == "" {
+		return nil, nil
+	}
+	pem, err := os.ReadFile(caFile)
 	if err != nil {
 		return nil, err
 	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", caFile)
+	}
+	return &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  pool,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
+
+// flagSet reports whether the named flag was given on the command line
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
 
-	return kubernetes.NewForConfig(config)
+// registerPprof adds the net/http/pprof handlers to mux under /debug/pprof/
+func registerPprof(mux *http.ServeMux) {
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+}
+
+// splitList parses a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// excludeList parses --exclude-cgroups. Unlike splitList an empty value
+// yields an empty non-nil slice, so the scanner doesn't fall back to its
+// defaults.
+func excludeList(value string) []string {
+	if items := splitList(value); items != nil {
+		return items
+	}
+	return []string{}
+}
+
+func getEnvString(key, defaultVal string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return defaultVal
 }
 
 func getEnvBool(key string, defaultVal bool) bool {
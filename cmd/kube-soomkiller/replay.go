@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rophy/kube-soomkiller/internal/cgroup"
+	"github.com/rophy/kube-soomkiller/internal/controller"
+	"k8s.io/klog/v2"
+)
+
+// runReplay implements "kube-soomkiller replay [flags] <snapshot-dir>": it
+// runs the kill decision logic against captured cgroup snapshots, oldest
+// first, and prints what would have been killed at each step. It never
+// talks to the Kubernetes API. Returns the process exit code.
+func runReplay(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s replay [flags] <snapshot-dir>\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Each subdirectory of snapshot-dir named by its capture time (RFC 3339 or Unix seconds) holds")
+		fmt.Fprintln(fs.Output(), "a cgroup tree (kubepods.slice), proc/{vmstat,meminfo,swaps} and optionally pods.json.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+
+	var (
+		swapThresholdPercent float64
+		swapGrowthThreshold  float64
+		killOnSwapLimit      bool
+		killOnMemoryHigh     bool
+		pidsThreshold        float64
+		nodeFraction         float64
+		killTopN             int
+		escalationDelay      time.Duration
+		protectedNamespaces  string
+		runtimePrefixes      string
+		kubepodsPath         string
+		excludeCgroups       string
+		unlimitedBasis       string
+		scoreWeights         string
+		scoreExpr            string
+		sortBy               string
+		showAll              bool
+	)
+	fs.Float64Var(&swapThresholdPercent, "swap-threshold-percent", 1.0, "Kill pods with swap usage > this % of memory limit")
+	fs.Float64Var(&swapGrowthThreshold, "swap-growth-threshold-bytes-per-sec", 0, "Also kill pods whose swap usage grows faster than this many bytes/sec (0 disables)")
+	fs.BoolVar(&killOnSwapLimit, "kill-on-swap-limit-events", false, "Also kill pods whose memory.swap.events max/fail counters increased since the previous snapshot")
+	fs.BoolVar(&killOnMemoryHigh, "kill-on-memory-high-events", false, "Also kill swapping pods whose memory.events high counter increased since the previous snapshot")
+	fs.Float64Var(&pidsThreshold, "pids-threshold-percent", 0, "Also kill swapping pods whose pids.current exceeds this % of pids.max (0 disables)")
+	fs.Float64Var(&nodeFraction, "swap-node-fraction-threshold", 0, "Also kill pods whose swap bytes exceed this fraction (0-1) of node SwapTotal (0 disables)")
+	fs.IntVar(&killTopN, "kill-top-n", 0, "Kill at most this many pods per snapshot, highest score first (0 = unlimited)")
+	fs.DurationVar(&escalationDelay, "escalation-delay", 0, "Only kill a pod if still over threshold this long after it first went over, by capture time (0 kills immediately)")
+	fs.StringVar(&protectedNamespaces, "protected-namespaces", "kube-system", "Comma-separated list of namespaces to never kill pods from")
+	fs.StringVar(&runtimePrefixes, "runtime-prefixes", strings.Join(cgroup.DefaultRuntimePrefixes, ","), "Comma-separated list of container scope prefixes identifying container cgroups")
+	fs.StringVar(&kubepodsPath, "kubepods-path", cgroup.DefaultKubepodsPath, "Kubelet's pod cgroup parent relative to each snapshot directory")
+	fs.StringVar(&excludeCgroups, "exclude-cgroups", strings.Join(cgroup.DefaultExcludePaths, ","), "Comma-separated cgroup path substrings, relative to --kubepods-path, that discovery skips (empty excludes nothing)")
+	fs.StringVar(&unlimitedBasis, "unlimited-basis", cgroup.UnlimitedBasisSentinel, "Swap percent denominator for containers with no memory or swap limit: sentinel or node-memory")
+	fs.StringVar(&scoreWeights, "score-weights", "swap=1,psi=0", "Kill ordering weights as name=value pairs (signals: swap, psi)")
+	fs.StringVar(&scoreExpr, "score-expr", "", "Kill ordering expression over swap_percent, psi_full_avg10, swap_bytes and mem_bytes, largest first (replaces --score-weights; empty uses --score-weights)")
+	fs.StringVar(&sortBy, "sort-by", controller.SortByScore, "Kill ordering: score (--score-weights) or footprint (memory + swap bytes, largest first)")
+	fs.BoolVar(&showAll, "show-all", false, "Also print pods using swap below every threshold")
+	klog.InitFlags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	weights, err := controller.ParseScoreWeights(scoreWeights)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "--score-weights is invalid: %v\n", err)
+		return 2
+	}
+	if sortBy != controller.SortByScore && sortBy != controller.SortByFootprint {
+		fmt.Fprintf(os.Stderr, "--sort-by must be %q or %q, got %q\n", controller.SortByScore, controller.SortByFootprint, sortBy)
+		return 2
+	}
+	var expr *controller.ScoreExpr
+	if scoreExpr != "" {
+		weightsSet := false
+		fs.Visit(func(f *flag.Flag) { weightsSet = weightsSet || f.Name == "score-weights" })
+		if weightsSet || sortBy == controller.SortByFootprint {
+			fmt.Fprintln(os.Stderr, "--score-expr cannot be combined with --score-weights or --sort-by=footprint")
+			return 2
+		}
+		if expr, err = controller.ParseScoreExpr(scoreExpr); err != nil {
+			fmt.Fprintf(os.Stderr, "--score-expr is invalid: %v\n", err)
+			return 2
+		}
+	}
+	if unlimitedBasis != cgroup.UnlimitedBasisSentinel && unlimitedBasis != cgroup.UnlimitedBasisNodeMemory {
+		fmt.Fprintf(os.Stderr, "--unlimited-basis must be %q or %q, got %q\n", cgroup.UnlimitedBasisSentinel, cgroup.UnlimitedBasisNodeMemory, unlimitedBasis)
+		return 2
+	}
+
+	snapshots, err := controller.LoadSnapshots(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	replayer := controller.NewReplayer(controller.Config{
+		SwapThresholdPercent:  swapThresholdPercent,
+		SwapGrowthThreshold:   swapGrowthThreshold,
+		KillOnSwapLimitEvents: killOnSwapLimit,
+		KillOnMemoryHigh:      killOnMemoryHigh,
+		PidsThresholdPercent:  pidsThreshold,
+		NodeFractionThreshold: nodeFraction,
+		KillTopN:              killTopN,
+		EscalationDelay:       escalationDelay,
+		ProtectedNamespaces:   splitList(protectedNamespaces),
+		ScoreWeights:          weights,
+		ScoreExpr:             expr,
+		SortBy:                sortBy,
+	}, cgroup.Options{
+		RuntimePrefixes: splitList(runtimePrefixes),
+		UnlimitedBasis:  unlimitedBasis,
+		KubepodsPath:    kubepodsPath,
+		ExcludePaths:    excludeList(excludeCgroups),
+	})
+
+	out := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(out, "TIME\tACTION\tPOD\tSWAP%\tREASON")
+	for _, snap := range snapshots {
+		decisions, err := replayer.Step(context.Background(), snap)
+		if err != nil {
+			out.Flush()
+			fmt.Fprintf(os.Stderr, "snapshot %s: %v\n", snap.Dir, err)
+			return 1
+		}
+		for _, d := range decisions {
+			if d.Action == controller.DecisionBelowThreshold && !showAll {
+				continue
+			}
+			// Replay kills are always dry-run; "would kill" reads better than its reason
+			action, reason := d.Action, d.Reason
+			if action == controller.DecisionDryRun {
+				action, reason = "would_kill", ""
+			}
+			fmt.Fprintf(out, "%s\t%s\t%s/%s\t%.1f\t%s\n", snap.At.UTC().Format(time.RFC3339), action, d.Namespace, d.Name, d.SwapPercent, reason)
+		}
+	}
+	out.Flush()
+	return 0
+}
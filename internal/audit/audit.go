@@ -0,0 +1,141 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Actions recorded in the audit log
+const (
+	ActionDeleted          = "deleted"
+	ActionDryRun           = "dry-run"
+	ActionDeleteFailed     = "delete-failed"
+	ActionSkippedProtected = "skipped-protected"
+)
+
+// Entry is a single kill decision, written as one JSON line
+type Entry struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Node        string    `json:"node"`
+	Namespace   string    `json:"namespace"`
+	Name        string    `json:"name"`
+	UID         string    `json:"uid"`
+	SwapBytes   int64     `json:"swapBytes"`
+	SwapPercent float64   `json:"swapPercent"`
+	Action      string    `json:"action"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// Logger appends audit entries to a file. Each entry is flushed and synced
+// so a crash doesn't lose recent decisions. If the file is rotated away
+// (renamed or removed) or a write fails, the path is reopened.
+type Logger struct {
+	path string
+
+	mu   sync.Mutex
+	file *os.File
+	w    *bufio.Writer
+}
+
+// Open opens (or creates) the audit log at path for appending
+func Open(path string) (*Logger, error) {
+	l := &Logger{path: path}
+	if err := l.reopen(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Record appends an entry to the audit log
+func (l *Logger) Record(entry Entry) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rotated() {
+		if err := l.reopen(); err != nil {
+			return err
+		}
+	}
+
+	if err := l.write(line); err != nil {
+		// Retry once on a fresh file handle
+		if reopenErr := l.reopen(); reopenErr != nil {
+			return fmt.Errorf("failed to write audit entry: %w (reopen: %v)", err, reopenErr)
+		}
+		if err := l.write(line); err != nil {
+			return fmt.Errorf("failed to write audit entry: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Close flushes and closes the audit log
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	flushErr := l.w.Flush()
+	closeErr := l.file.Close()
+	l.file = nil
+	if flushErr != nil {
+		return flushErr
+	}
+	return closeErr
+}
+
+func (l *Logger) write(line []byte) error {
+	if _, err := l.w.Write(line); err != nil {
+		return err
+	}
+	if err := l.w.Flush(); err != nil {
+		return err
+	}
+	return l.file.Sync()
+}
+
+// rotated reports whether the path no longer refers to the open file
+func (l *Logger) rotated() bool {
+	pathInfo, err := os.Stat(l.path)
+	if err != nil {
+		return true
+	}
+	fileInfo, err := l.file.Stat()
+	if err != nil {
+		return true
+	}
+	return !os.SameFile(pathInfo, fileInfo)
+}
+
+func (l *Logger) reopen() error {
+	if l.file != nil {
+		l.file.Close()
+		l.file = nil
+	}
+
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log %s: %w", l.path, err)
+	}
+	l.file = file
+	l.w = bufio.NewWriter(file)
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readEntries(t *testing.T, path string) []Entry {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to parse audit line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	l, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer l.Close()
+
+	if err := l.Record(Entry{Node: "node1", Namespace: "default", Name: "pod1", SwapPercent: 12.5, Action: ActionDeleted}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := l.Record(Entry{Node: "node1", Namespace: "kube-system", Name: "pod2", Action: ActionSkippedProtected}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	entries := readEntries(t, path)
+	if len(entries) != 2 {
+		t.Fatalf("audit log has %d entries, want 2", len(entries))
+	}
+	if entries[0].Name != "pod1" || entries[0].Action != ActionDeleted || entries[0].SwapPercent != 12.5 {
+		t.Errorf("entry[0] = %+v", entries[0])
+	}
+	if entries[0].Timestamp.IsZero() {
+		t.Error("entry[0] timestamp not set")
+	}
+	if entries[1].Action != ActionSkippedProtected {
+		t.Errorf("entry[1].Action = %q, want %q", entries[1].Action, ActionSkippedProtected)
+	}
+}
+
+func TestRecord_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+
+	l, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer l.Close()
+
+	if err := l.Record(Entry{Name: "before", Action: ActionDryRun}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	// Rotate the file out from under the logger
+	if err := os.Rename(path, filepath.Join(dir, "audit.log.1")); err != nil {
+		t.Fatalf("Failed to rotate audit log: %v", err)
+	}
+
+	if err := l.Record(Entry{Name: "after", Action: ActionDryRun}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	entries := readEntries(t, path)
+	if len(entries) != 1 || entries[0].Name != "after" {
+		t.Errorf("new audit log entries = %+v, want only 'after'", entries)
+	}
+
+	rotated := readEntries(t, filepath.Join(dir, "audit.log.1"))
+	if len(rotated) != 1 || rotated[0].Name != "before" {
+		t.Errorf("rotated audit log entries = %+v, want only 'before'", rotated)
+	}
+}
+
+func TestOpen_InvalidPath(t *testing.T) {
+	if _, err := Open(filepath.Join(t.TempDir(), "missing", "audit.log")); err == nil {
+		t.Error("Open() expected error for missing parent directory")
+	}
+}
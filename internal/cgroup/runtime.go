@@ -0,0 +1,102 @@
+package cgroup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Container runtimes reported by DetectRuntimes
+const (
+	RuntimeContainerd = "containerd"
+	RuntimeCRIO       = "crio"
+	RuntimeDocker     = "docker"
+	RuntimeUnknown    = "unknown"
+)
+
+// runtimeByPrefix maps well-known scope prefixes to their runtime
+var runtimeByPrefix = map[string]string{
+	"cri-containerd-":   RuntimeContainerd,
+	"crio-":             RuntimeCRIO,
+	DockerRuntimePrefix: RuntimeDocker,
+}
+
+// runtimeSockets are checked when no container scope exists yet. They are
+// only visible if the host's /run is mounted into the container.
+var runtimeSockets = []struct {
+	runtime string
+	prefix  string
+	path    string
+}{
+	{RuntimeContainerd, "cri-containerd-", "/run/containerd/containerd.sock"},
+	{RuntimeCRIO, "crio-", "/var/run/crio/crio.sock"},
+}
+
+// DetectRuntime returns the first runtime reported by DetectRuntimes
+func (s *Scanner) DetectRuntime() string {
+	return s.DetectRuntimes()[0]
+}
+
+// DetectRuntimes identifies the node's container runtimes from its
+// recognized container scopes, in the order first seen, falling back to the
+// first well-known runtime socket. A node mid-migration can run both
+// containerd and CRI-O pods; every configured prefix is always matched, so
+// each scope is attributed to its pod whichever runtime created it. The
+// first runtime's prefix is moved to the front so it is tried first. Scopes
+// with a configured prefix that isn't well known report the prefix without
+// its trailing dash. Returns [RuntimeUnknown] if nothing is found. Call it
+// before StartWatch.
+func (s *Scanner) DetectRuntimes() []string {
+	var prefixes []string
+	seen := make(map[string]bool)
+	filepath.WalkDir(s.kubepodsDir(), func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if s.isExcluded(path) {
+			return filepath.SkipDir
+		}
+		if strings.HasSuffix(d.Name(), ".scope") {
+			if prefix := s.matchRuntimePrefix(d.Name()); prefix != "" && !seen[prefix] {
+				seen[prefix] = true
+				prefixes = append(prefixes, prefix)
+			}
+		}
+		return nil
+	})
+
+	if len(prefixes) > 0 {
+		s.preferRuntimePrefix(prefixes[0])
+		runtimes := make([]string, 0, len(prefixes))
+		for _, prefix := range prefixes {
+			if runtime, ok := runtimeByPrefix[prefix]; ok {
+				runtimes = append(runtimes, runtime)
+			} else {
+				runtimes = append(runtimes, strings.TrimSuffix(prefix, "-"))
+			}
+		}
+		return runtimes
+	}
+
+	for _, sock := range runtimeSockets {
+		if _, err := os.Stat(sock.path); err == nil {
+			s.preferRuntimePrefix(sock.prefix)
+			return []string{sock.runtime}
+		}
+	}
+
+	return []string{RuntimeUnknown}
+}
+
+// preferRuntimePrefix moves prefix to the front of the configured prefixes
+// if present, keeping the order of the rest
+func (s *Scanner) preferRuntimePrefix(prefix string) {
+	for i, p := range s.runtimePrefixes {
+		if p != prefix {
+			continue
+		}
+		prefixes := append([]string{prefix}, s.runtimePrefixes[:i]...)
+		s.runtimePrefixes = append(prefixes, s.runtimePrefixes[i+1:]...)
+		return
+	}
+}
@@ -0,0 +1,105 @@
+package cgroup
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDetectRuntime(t *testing.T) {
+	tests := []struct {
+		name         string
+		scope        string
+		prefixes     []string
+		want         string
+		wantPrefixes []string
+	}{
+		{"containerd", "cri-containerd-abc.scope", nil, RuntimeContainerd, []string{"cri-containerd-", "crio-"}},
+		{"crio moves to front", "crio-abc.scope", nil, RuntimeCRIO, []string{"crio-", "cri-containerd-"}},
+		{"docker", "docker-abc.scope", []string{"cri-containerd-", "crio-", "docker-"}, RuntimeDocker, []string{"docker-", "cri-containerd-", "crio-"}},
+		{"custom prefix", "kata-abc.scope", []string{"cri-containerd-", "kata-"}, "kata", []string{"kata-", "cri-containerd-"}},
+		{"unrecognized scope", "kata-abc.scope", nil, RuntimeUnknown, []string{"cri-containerd-", "crio-"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			scopePath := filepath.Join(tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice", tt.scope)
+			if err := os.MkdirAll(scopePath, 0755); err != nil {
+				t.Fatalf("Failed to create scope: %v", err)
+			}
+
+			// Keep the host's sockets out of the test
+			saved := runtimeSockets
+			runtimeSockets = nil
+			defer func() { runtimeSockets = saved }()
+
+			s := NewScannerWithOptions(tmpDir, Options{RuntimePrefixes: tt.prefixes})
+			if got := s.DetectRuntime(); got != tt.want {
+				t.Errorf("DetectRuntime() = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(s.runtimePrefixes, tt.wantPrefixes) {
+				t.Errorf("runtimePrefixes = %v, want %v", s.runtimePrefixes, tt.wantPrefixes)
+			}
+		})
+	}
+}
+
+func TestDetectRuntime_SocketFallback(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "kubepods.slice"), 0755); err != nil {
+		t.Fatalf("Failed to create kubepods.slice: %v", err)
+	}
+	sock := filepath.Join(tmpDir, "crio.sock")
+	if err := os.WriteFile(sock, nil, 0644); err != nil {
+		t.Fatalf("Failed to create socket file: %v", err)
+	}
+
+	saved := runtimeSockets
+	runtimeSockets = []struct {
+		runtime string
+		prefix  string
+		path    string
+	}{
+		{RuntimeContainerd, "cri-containerd-", filepath.Join(tmpDir, "containerd.sock")},
+		{RuntimeCRIO, "crio-", sock},
+	}
+	defer func() { runtimeSockets = saved }()
+
+	s := NewScanner(tmpDir)
+	if got := s.DetectRuntime(); got != RuntimeCRIO {
+		t.Errorf("DetectRuntime() = %q, want %q", got, RuntimeCRIO)
+	}
+	if s.runtimePrefixes[0] != "crio-" {
+		t.Errorf("runtimePrefixes = %v, want crio- first", s.runtimePrefixes)
+	}
+}
+
+func TestDetectRuntimes_Mixed(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// A node migrating from CRI-O to containerd runs pods under both
+	for _, scope := range []string{
+		"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/crio-abc.scope",
+		"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/crio-def.scope",
+		"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod456.slice/cri-containerd-ghi.scope",
+	} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, scope), 0755); err != nil {
+			t.Fatalf("Failed to create scope: %v", err)
+		}
+	}
+
+	saved := runtimeSockets
+	runtimeSockets = nil
+	defer func() { runtimeSockets = saved }()
+
+	s := NewScanner(tmpDir)
+	if got, want := s.DetectRuntimes(), []string{RuntimeCRIO, RuntimeContainerd}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectRuntimes() = %v, want %v", got, want)
+	}
+	// Reordering only changes match priority; both prefixes stay configured
+	if want := []string{"crio-", "cri-containerd-"}; !reflect.DeepEqual(s.runtimePrefixes, want) {
+		t.Errorf("runtimePrefixes = %v, want %v", s.runtimePrefixes, want)
+	}
+}
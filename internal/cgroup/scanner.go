@@ -2,26 +2,135 @@ package cgroup
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 
 	"k8s.io/klog/v2"
 )
 
+// DefaultRuntimePrefixes are the container scope prefixes recognized when none are configured:
+// - containerd: cri-containerd-<id>.scope
+// - CRI-O: crio-<id>.scope
+//
+// Docker (cri-dockerd) scopes use docker-<id>.scope and are recognized when
+// DockerRuntimePrefix is added to the configured prefixes.
+var DefaultRuntimePrefixes = []string{"cri-containerd-", "crio-"}
+
+// DockerRuntimePrefix is the scope prefix used by Docker-based runtimes
+const DockerRuntimePrefix = "docker-"
+
 // Scanner handles cgroup filesystem operations
 type Scanner struct {
 	cgroupRoot string
+	procPath   string
 	vmstatPath string
+
+	// Kubelet's pod cgroup parent, relative to cgroupRoot (e.g. "kubepods.slice")
+	kubepodsPath string
+
+	// Scope name prefixes identifying container cgroups (e.g. "cri-containerd-")
+	runtimePrefixes []string
+
+	// Path substrings of cgroups that discovery never descends into (e.g. "system.slice")
+	excludePaths []string
+
+	// watcher serves FindPodCgroups from a cached set when watch discovery is enabled
+	watcher *cgroupWatcher
+
+	// Denominator for swap percent when no memory limit is set
+	unlimitedBasis string
+
+	// Node MemTotal, read once on first use with UnlimitedBasisNodeMemory
+	memTotalOnce sync.Once
+	memTotal     int64
+}
+
+// Options configures optional Scanner behavior. Zero values select defaults.
+type Options struct {
+	// RuntimePrefixes are the scope name prefixes identifying container cgroups
+	RuntimePrefixes []string
+	// ProcPath is the procfs mount to read node-level stats from (default /proc)
+	ProcPath string
+	// UnlimitedBasis selects the swap percent denominator for containers
+	// without a memory limit (default UnlimitedBasisSentinel)
+	UnlimitedBasis string
+	// KubepodsPath is the kubelet's pod cgroup parent relative to the cgroup
+	// root (default DefaultKubepodsPath)
+	KubepodsPath string
+	// ExcludePaths are substrings of cgroup paths, relative to the pod cgroup
+	// parent, that discovery skips along with everything below them. nil
+	// selects DefaultExcludePaths; an empty non-nil slice excludes nothing.
+	ExcludePaths []string
 }
 
-// NewScanner creates a new cgroup scanner
+// DefaultProcPath is the procfs mount used when Options.ProcPath is empty
+const DefaultProcPath = "/proc"
+
+// DefaultKubepodsPath is the pod cgroup parent used when Options.KubepodsPath
+// is empty: the kubelet's default with the systemd cgroup driver
+const DefaultKubepodsPath = "kubepods.slice"
+
+// DefaultExcludePaths are the host cgroups skipped when Options.ExcludePaths
+// is nil: systemd services and the init process never hold pod containers
+var DefaultExcludePaths = []string{"system.slice", "init.scope"}
+
+// Swap percent bases for containers whose memory.max is "max"
+const (
+	// UnlimitedBasisSentinel divides by a finite memory.swap.max, else by the
+	// 1<<62 "max" sentinel, so such containers read ~0%
+	UnlimitedBasisSentinel = "sentinel"
+	// UnlimitedBasisNodeMemory divides by node MemTotal from /proc/meminfo,
+	// ahead of any memory.swap.max
+	UnlimitedBasisNodeMemory = "node-memory"
+)
+
+// NewScanner creates a new cgroup scanner with default options
 func NewScanner(cgroupRoot string) *Scanner {
+	return NewScannerWithOptions(cgroupRoot, Options{})
+}
+
+// NewScannerWithOptions creates a new cgroup scanner
+func NewScannerWithOptions(cgroupRoot string, opts Options) *Scanner {
+	prefixes := opts.RuntimePrefixes
+	if len(prefixes) == 0 {
+		prefixes = DefaultRuntimePrefixes
+	}
+
+	procPath := opts.ProcPath
+	if procPath == "" {
+		procPath = DefaultProcPath
+	}
+
+	unlimitedBasis := opts.UnlimitedBasis
+	if unlimitedBasis == "" {
+		unlimitedBasis = UnlimitedBasisSentinel
+	}
+
+	kubepodsPath := opts.KubepodsPath
+	if kubepodsPath == "" {
+		kubepodsPath = DefaultKubepodsPath
+	}
+
+	excludePaths := opts.ExcludePaths
+	if excludePaths == nil {
+		excludePaths = DefaultExcludePaths
+	}
+
 	return &Scanner{
-		cgroupRoot: cgroupRoot,
-		vmstatPath: "/proc/vmstat",
+		cgroupRoot:      cgroupRoot,
+		procPath:        procPath,
+		vmstatPath:      filepath.Join(procPath, "vmstat"),
+		kubepodsPath:    kubepodsPath,
+		runtimePrefixes: prefixes,
+		excludePaths:    excludePaths,
+		unlimitedBasis:  unlimitedBasis,
 	}
 }
 
@@ -30,6 +139,27 @@ func (s *Scanner) CgroupRoot() string {
 	return s.cgroupRoot
 }
 
+// kubepodsDir returns the absolute path of the pod cgroup parent
+func (s *Scanner) kubepodsDir() string {
+	return filepath.Join(s.cgroupRoot, s.kubepodsPath)
+}
+
+// isExcluded reports whether the cgroup directory at path (absolute) matches
+// an exclude substring. Only the part below the pod cgroup parent is matched,
+// so a parent nested under e.g. system.slice is never excluded itself.
+func (s *Scanner) isExcluded(path string) bool {
+	relPath, err := filepath.Rel(s.kubepodsDir(), path)
+	if err != nil || relPath == "." {
+		return false
+	}
+	for _, exclude := range s.excludePaths {
+		if strings.Contains(relPath, exclude) {
+			return true
+		}
+	}
+	return false
+}
+
 // ValidateEnvironment checks that the system meets requirements:
 // - cgroup v2 (unified hierarchy)
 // - systemd cgroup driver (kubepods.slice layout)
@@ -41,9 +171,9 @@ func (s *Scanner) ValidateEnvironment() error {
 	}
 
 	// Check for systemd cgroup driver: look for kubepods.slice directory
-	kubepodsSlice := filepath.Join(s.cgroupRoot, "kubepods.slice")
+	kubepodsSlice := s.kubepodsDir()
 	if _, err := os.Stat(kubepodsSlice); os.IsNotExist(err) {
-		return fmt.Errorf("systemd cgroup driver not detected: %s not found (cgroupfs driver is not supported)", kubepodsSlice)
+		return fmt.Errorf("systemd cgroup driver not detected: %s not found (cgroupfs driver is not supported; set --kubepods-path if the kubelet uses a custom cgroup root)", kubepodsSlice)
 	}
 
 	// Check for swap support: look for memory.swap.max in kubepods.slice
@@ -52,6 +182,45 @@ func (s *Scanner) ValidateEnvironment() error {
 		return fmt.Errorf("swap not enabled: %s not found", swapMax)
 	}
 
+	return s.validateSwapAccounting()
+}
+
+// errFoundContainer stops the walk once the first container cgroup is found
+var errFoundContainer = errors.New("found container cgroup")
+
+// validateSwapAccounting reads memory.swap.current from the first container
+// cgroup found. Per-container swap accounting can be disabled by the kernel
+// (swapaccount=0) even though memory.swap.max exists on kubepods.slice, which
+// would silently prevent any kills. If no containers are running yet the
+// check is skipped.
+func (s *Scanner) validateSwapAccounting() error {
+	kubepodsSlice := s.kubepodsDir()
+
+	var containerPath string
+	filepath.WalkDir(kubepodsSlice, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if s.isExcluded(path) {
+			return filepath.SkipDir
+		}
+		if strings.HasSuffix(d.Name(), ".scope") && s.isContainerScope(d.Name()) {
+			containerPath = path
+			return errFoundContainer
+		}
+		return nil
+	})
+
+	if containerPath == "" {
+		klog.InfoS("No container cgroups found, skipping swap accounting check")
+		return nil
+	}
+
+	swapCurrent := filepath.Join(containerPath, "memory.swap.current")
+	if _, err := readInt64File(swapCurrent); err != nil {
+		return fmt.Errorf("swap accounting not available: cannot read %s: %w (check the swapaccount=1 kernel boot parameter)", swapCurrent, err)
+	}
+
 	return nil
 }
 
@@ -64,18 +233,37 @@ type ScanResult struct {
 }
 
 // FindPodCgroups finds all container cgroup paths under kubepods.slice
-// Supports both containerd (cri-containerd-) and CRI-O (crio-) runtimes
+// Scopes are recognized by the configured runtime prefixes (containerd and CRI-O by default)
 // Layout: kubepods.slice/kubepods-<qos>.slice/kubepods-<qos>-pod<uid>.slice/<runtime>-<id>.scope
+// When watch discovery is enabled (see StartWatch), the cached set is returned instead.
 func (s *Scanner) FindPodCgroups() (*ScanResult, error) {
+	return s.FindPodCgroupsContext(context.Background())
+}
+
+// FindPodCgroupsContext is FindPodCgroups, aborting the walk with ctx.Err()
+// once ctx is done
+func (s *Scanner) FindPodCgroupsContext(ctx context.Context) (*ScanResult, error) {
+	if s.watcher != nil {
+		return s.watcher.snapshot(), nil
+	}
+	return s.walkPodCgroups(ctx)
+}
+
+// walkPodCgroups performs a full filesystem walk of kubepods.slice
+func (s *Scanner) walkPodCgroups(ctx context.Context) (*ScanResult, error) {
 	result := &ScanResult{}
 
-	kubepodsPath := filepath.Join(s.cgroupRoot, "kubepods.slice")
+	kubepodsPath := s.kubepodsDir()
 	if _, err := os.Stat(kubepodsPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("kubepods.slice not found at %s", kubepodsPath)
+		return nil, fmt.Errorf("%s not found at %s", s.kubepodsPath, kubepodsPath)
 	}
 
 	// Walk through kubepods hierarchy to find container cgroups
 	err := filepath.Walk(kubepodsPath, func(path string, info os.FileInfo, err error) error {
+		// A pathological tree must not hold up shutdown
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		if err != nil {
 			return nil // Skip errors, continue walking
 		}
@@ -83,6 +271,9 @@ func (s *Scanner) FindPodCgroups() (*ScanResult, error) {
 		if !info.IsDir() {
 			return nil
 		}
+		if s.isExcluded(path) {
+			return filepath.SkipDir
+		}
 
 		name := info.Name()
 		if !strings.HasSuffix(name, ".scope") {
@@ -91,10 +282,7 @@ func (s *Scanner) FindPodCgroups() (*ScanResult, error) {
 
 		relPath, _ := filepath.Rel(s.cgroupRoot, path)
 
-		// Match container cgroup directories:
-		// - containerd: cri-containerd-<id>.scope
-		// - CRI-O: crio-<id>.scope
-		if strings.HasPrefix(name, "cri-containerd-") || strings.HasPrefix(name, "crio-") {
+		if s.isContainerScope(name) {
 			result.Cgroups = append(result.Cgroups, relPath)
 		} else {
 			result.Unrecognized = append(result.Unrecognized, relPath)
@@ -102,8 +290,26 @@ func (s *Scanner) FindPodCgroups() (*ScanResult, error) {
 
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
 
-	return result, err
+// isContainerScope reports whether a .scope directory name matches a configured runtime prefix
+func (s *Scanner) isContainerScope(name string) bool {
+	return s.matchRuntimePrefix(name) != ""
+}
+
+// matchRuntimePrefix returns the configured runtime prefix that name starts with, or ""
+func (s *Scanner) matchRuntimePrefix(name string) string {
+	for _, prefix := range s.runtimePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return prefix
+		}
+	}
+	return ""
 }
 
 // PSI represents Pressure Stall Information for a cgroup
@@ -124,11 +330,115 @@ type ContainerMetrics struct {
 	SwapCurrent   int64 // bytes (memory.swap.current)
 	SwapMax       int64 // bytes (memory.swap.max limit)
 	MemoryCurrent int64 // bytes (memory.current)
-	MemoryMax     int64 // bytes (memory.max limit)
+	MemoryMax     int64 // bytes (memory.max limit, or the pod slice's if the container's is "max")
 	PSI           PSI
+	Stat          MemoryStat   // optional, zero if memory.stat is unavailable
+	SwapEvents    SwapEvents   // optional, zero if memory.swap.events is unavailable
+	MemoryEvents  MemoryEvents // optional, zero if memory.events is unavailable
+	PidsCurrent   int64        // optional, zero if the pids controller is not enabled (pids.current)
+	PidsMax       int64        // optional, UnlimitedBytes if pids.max is "max" or unavailable
+	NodeMemTotal  int64        // node MemTotal bytes with UnlimitedBasisNodeMemory, else 0
 }
 
-// GetContainerMetrics retrieves metrics for a container given its cgroup path
+// UnlimitedBytes is returned for memory.max and memory.swap.max when the
+// file reads "max" (~4 exabytes)
+const UnlimitedBytes int64 = 1 << 62
+
+// SwapPercentDecimals is the number of decimal places swap percentages are
+// rounded to (0.1%), so thresholds, logs, events and metrics all see the
+// same value and tiny fluctuations don't churn metric samples
+const SwapPercentDecimals = 1
+
+// RoundPercent rounds a percentage to SwapPercentDecimals places, halves
+// away from zero
+func RoundPercent(percent float64) float64 {
+	scale := math.Pow10(SwapPercentDecimals)
+	return math.Round(percent*scale) / scale
+}
+
+// SwapPercent returns swap usage as a percentage of memory.max. When
+// memory.max is unlimited, NodeMemTotal is the denominator if known
+// (UnlimitedBasisNodeMemory). Otherwise, or when memory.max is zero (e.g. a
+// cgroup mid-teardown), a finite memory.swap.max is used instead. With none
+// of these the result is relative to the unlimited sentinel and effectively
+// 0. The result is rounded with RoundPercent and is never NaN or Inf.
+func (m *ContainerMetrics) SwapPercent() float64 {
+	limit := m.MemoryMax
+	if limit == UnlimitedBytes && m.NodeMemTotal > 0 {
+		limit = m.NodeMemTotal
+	} else if (limit <= 0 || limit == UnlimitedBytes) && m.SwapMax > 0 && m.SwapMax != UnlimitedBytes {
+		limit = m.SwapMax
+	}
+	if limit <= 0 {
+		return 0
+	}
+
+	percent := float64(m.SwapCurrent) / float64(limit) * 100
+	if math.IsNaN(percent) || math.IsInf(percent, 0) {
+		return 0
+	}
+	return RoundPercent(percent)
+}
+
+// PidsPercent returns pids.current as a percentage of pids.max, or 0 when
+// pids.max is unlimited or unknown
+func (m *ContainerMetrics) PidsPercent() float64 {
+	if m.PidsMax <= 0 || m.PidsMax == UnlimitedBytes {
+		return 0
+	}
+	return float64(m.PidsCurrent) / float64(m.PidsMax) * 100
+}
+
+// SwapOfLimitPercent returns swap usage as a percentage of memory.swap.max,
+// how close the container is to being swap-throttled, or 0 when
+// memory.swap.max is unlimited or unknown. Unlike SwapPercent it is never
+// relative to the memory limit. Rounded like SwapPercent.
+func (m *ContainerMetrics) SwapOfLimitPercent() float64 {
+	if m.SwapMax <= 0 || m.SwapMax == UnlimitedBytes {
+		return 0
+	}
+	return RoundPercent(float64(m.SwapCurrent) / float64(m.SwapMax) * 100)
+}
+
+// SwapEvents holds the cumulative counters from memory.swap.events
+type SwapEvents struct {
+	High uint64 // times swap usage exceeded memory.swap.high
+	Max  uint64 // times swap allocation was about to exceed memory.swap.max
+	Fail uint64 // times swap allocation failed (limit hit or swap exhausted)
+}
+
+// MemoryEvents holds the cumulative counters from memory.events
+type MemoryEvents struct {
+	High    uint64 // times the cgroup was throttled and reclaimed at memory.high
+	Max     uint64 // times usage was about to exceed memory.max
+	OOMKill uint64 // processes killed by the OOM killer
+}
+
+// MemoryStat holds swap-related fields from memory.stat. Fields not
+// reported by the kernel (e.g. zswap disabled or older kernels) are zero.
+type MemoryStat struct {
+	SwapCached int64 // bytes (swapcached): swapped-out memory also cached in RAM
+	Zswap      int64 // bytes (zswap): memory consumed by compressed swap pool
+	Zswapped   int64 // bytes (zswapped): uncompressed size of memory stored in zswap
+}
+
+// ReadError is returned by GetContainerMetrics when a required cgroup file
+// can't be read or parsed
+type ReadError struct {
+	File string // file name within the container cgroup, e.g. "memory.swap.current"
+	Err  error
+}
+
+func (e *ReadError) Error() string {
+	return fmt.Sprintf("failed to read %s: %v", e.File, e.Err)
+}
+
+func (e *ReadError) Unwrap() error {
+	return e.Err
+}
+
+// GetContainerMetrics retrieves metrics for a container given its cgroup path.
+// A required file that can't be read fails with a *ReadError.
 func (s *Scanner) GetContainerMetrics(cgroupPath string) (*ContainerMetrics, error) {
 	fullPath := filepath.Join(s.cgroupRoot, cgroupPath)
 
@@ -139,41 +449,129 @@ func (s *Scanner) GetContainerMetrics(cgroupPath string) (*ContainerMetrics, err
 	// Read memory.swap.current
 	swapCurrent, err := readInt64File(filepath.Join(fullPath, "memory.swap.current"))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read memory.swap.current: %w", err)
+		return nil, &ReadError{File: "memory.swap.current", Err: err}
 	}
 	metrics.SwapCurrent = swapCurrent
 
 	// Read memory.swap.max (uses same format as memory.max: number or "max")
 	swapMax, err := readMemoryMax(filepath.Join(fullPath, "memory.swap.max"))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read memory.swap.max: %w", err)
+		return nil, &ReadError{File: "memory.swap.max", Err: err}
 	}
 	metrics.SwapMax = swapMax
 
 	// Read memory.current
 	memoryCurrent, err := readInt64File(filepath.Join(fullPath, "memory.current"))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read memory.current: %w", err)
+		return nil, &ReadError{File: "memory.current", Err: err}
 	}
 	metrics.MemoryCurrent = memoryCurrent
 
 	// Read memory.max
 	memoryMax, err := readMemoryMax(filepath.Join(fullPath, "memory.max"))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read memory.max: %w", err)
+		return nil, &ReadError{File: "memory.max", Err: err}
+	}
+	if memoryMax == UnlimitedBytes {
+		// Pod-level limits leave the container's own memory.max at "max"
+		memoryMax = s.inheritedMemoryMax(fullPath)
 	}
 	metrics.MemoryMax = memoryMax
 
 	// Read memory.pressure (PSI)
 	psi, err := readPSI(filepath.Join(fullPath, "memory.pressure"))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read memory.pressure: %w", err)
+		return nil, &ReadError{File: "memory.pressure", Err: err}
 	}
 	metrics.PSI = *psi
 
+	// Read memory.stat (optional: detailed accounting is best-effort)
+	stat, err := readMemoryStat(filepath.Join(fullPath, "memory.stat"))
+	if err != nil {
+		klog.V(4).InfoS("Failed to read memory.stat", "cgroupPath", cgroupPath, "err", err)
+	} else {
+		metrics.Stat = *stat
+	}
+
+	// Read memory.swap.events (optional: absent on older kernels)
+	events, err := readSwapEvents(filepath.Join(fullPath, "memory.swap.events"))
+	if err != nil {
+		klog.V(4).InfoS("Failed to read memory.swap.events", "cgroupPath", cgroupPath, "err", err)
+	} else {
+		metrics.SwapEvents = *events
+	}
+
+	// Read memory.events (optional: high breaches precede heavy swapping)
+	memEvents, err := readMemoryEvents(filepath.Join(fullPath, "memory.events"))
+	if err != nil {
+		klog.V(4).InfoS("Failed to read memory.events", "cgroupPath", cgroupPath, "err", err)
+	} else {
+		metrics.MemoryEvents = *memEvents
+	}
+
+	// Read pids.current and pids.max (optional: the pids controller may not be enabled)
+	metrics.PidsMax = UnlimitedBytes
+	if pidsCurrent, err := readInt64File(filepath.Join(fullPath, "pids.current")); err == nil {
+		metrics.PidsCurrent = pidsCurrent
+		// pids.max uses the same number-or-"max" format as memory.max
+		if pidsMax, err := readMemoryMax(filepath.Join(fullPath, "pids.max")); err == nil {
+			metrics.PidsMax = pidsMax
+		}
+	} else {
+		klog.V(4).InfoS("Failed to read pids.current", "cgroupPath", cgroupPath, "err", err)
+	}
+
+	if s.unlimitedBasis == UnlimitedBasisNodeMemory {
+		metrics.NodeMemTotal = s.nodeMemTotal()
+	}
+
 	return metrics, nil
 }
 
+// inheritedMemoryMax returns the memory.max of the nearest ancestor of the
+// container cgroup at fullPath, up to and including its pod slice, that sets
+// a concrete limit, or UnlimitedBytes if none does. QoS and kubepods slices
+// are not consulted: their limits are node-wide, not the pod's.
+func (s *Scanner) inheritedMemoryMax(fullPath string) int64 {
+	kubepodsDir := s.kubepodsDir()
+	for dir := filepath.Dir(fullPath); strings.HasPrefix(dir, kubepodsDir+string(filepath.Separator)); dir = filepath.Dir(dir) {
+		if limit, err := readMemoryMax(filepath.Join(dir, "memory.max")); err == nil && limit != UnlimitedBytes {
+			klog.V(4).InfoS("Using ancestor memory.max for unlimited container", "cgroupPath", fullPath, "ancestor", dir, "limit", limit)
+			return limit
+		}
+		if name := filepath.Base(dir); strings.HasSuffix(name, ".slice") && strings.Contains(name, "-pod") {
+			break
+		}
+	}
+	return UnlimitedBytes
+}
+
+// nodeMemTotal returns node MemTotal, read once. It is 0 if /proc/meminfo
+// can't be read, which falls back to the sentinel basis.
+func (s *Scanner) nodeMemTotal() int64 {
+	s.memTotalOnce.Do(func() {
+		total, err := s.GetMemTotal()
+		if err != nil {
+			klog.ErrorS(err, "Failed to read node MemTotal, using the unlimited sentinel for swap percent")
+			return
+		}
+		s.memTotal = total
+	})
+	return s.memTotal
+}
+
+// HasProcesses reports whether the cgroup has any live processes in
+// cgroup.procs. If the file can't be read the cgroup is assumed live, so a
+// read error never hides a container's swap.
+func (s *Scanner) HasProcesses(cgroupPath string) bool {
+	data, err := os.ReadFile(filepath.Join(s.cgroupRoot, cgroupPath, "cgroup.procs"))
+	if err != nil {
+		klog.V(4).InfoS("Failed to read cgroup.procs", "cgroupPath", cgroupPath, "err", err)
+		return true
+	}
+	return len(strings.TrimSpace(string(data))) > 0
+}
+
 // SwapIOStats represents node-level swap I/O counters from /proc/vmstat
 type SwapIOStats struct {
 	PswpIn  uint64 // pages swapped in (cumulative)
@@ -182,45 +580,186 @@ type SwapIOStats struct {
 
 // GetSwapIOStats retrieves swap I/O counters from /proc/vmstat
 func (s *Scanner) GetSwapIOStats() (*SwapIOStats, error) {
+	counters, err := s.GetVmstatCounters([]string{"pswpin", "pswpout"})
+	if err != nil {
+		return nil, err
+	}
+	return &SwapIOStats{
+		PswpIn:  counters["pswpin"],
+		PswpOut: counters["pswpout"],
+	}, nil
+}
+
+// GetVmstatCounters reads the named cumulative counters from /proc/vmstat.
+// Counters the kernel doesn't report (e.g. zswpout without zswap) or that
+// fail to parse are absent from the result.
+func (s *Scanner) GetVmstatCounters(names []string) (map[string]uint64, error) {
 	file, err := os.Open(s.vmstatPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open %s: %w", s.vmstatPath, err)
 	}
 	defer file.Close()
 
-	stats := &SwapIOStats{}
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	counters := make(map[string]uint64, len(names))
 	scanner := bufio.NewScanner(file)
 
 	for scanner.Scan() {
 		line := scanner.Text()
 		fields := strings.Fields(line)
-		if len(fields) != 2 {
+		if len(fields) != 2 || !wanted[fields[0]] {
 			continue
 		}
 
-		switch fields[0] {
-		case "pswpin":
-			val, err := strconv.ParseUint(fields[1], 10, 64)
-			if err != nil {
-				klog.V(4).InfoS("Failed to parse pswpin value", "value", fields[1], "err", err)
-			} else {
-				stats.PswpIn = val
-			}
-		case "pswpout":
-			val, err := strconv.ParseUint(fields[1], 10, 64)
-			if err != nil {
-				klog.V(4).InfoS("Failed to parse pswpout value", "value", fields[1], "err", err)
-			} else {
-				stats.PswpOut = val
-			}
+		val, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			klog.V(4).InfoS("Failed to parse vmstat value", "counter", fields[0], "value", fields[1], "err", err)
+			continue
 		}
+		counters[fields[0]] = val
 	}
 
 	if err := scanner.Err(); err != nil {
 		return nil, fmt.Errorf("failed to read %s: %w", s.vmstatPath, err)
 	}
 
-	return stats, nil
+	return counters, nil
+}
+
+// SwapDevice is an active swap backing device from /proc/swaps
+type SwapDevice struct {
+	Name     string // device or file path (e.g. /dev/zram0, /swapfile)
+	Type     string // partition or file
+	Size     int64  // bytes
+	Used     int64  // bytes
+	Priority int
+}
+
+// GetSwapDevices retrieves the active swap devices from /proc/swaps.
+// An empty result means no swap is configured on the node.
+func (s *Scanner) GetSwapDevices() ([]SwapDevice, error) {
+	path := filepath.Join(s.procPath, "swaps")
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var devices []SwapDevice
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		// Parse: Filename Type Size Used Priority (sizes in KiB, header on first line)
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 5 || fields[0] == "Filename" {
+			continue
+		}
+
+		size, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			klog.V(4).InfoS("Failed to parse swap size", "device", fields[0], "value", fields[2], "err", err)
+			continue
+		}
+		used, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			klog.V(4).InfoS("Failed to parse swap used", "device", fields[0], "value", fields[3], "err", err)
+			continue
+		}
+		priority, _ := strconv.Atoi(fields[4])
+
+		devices = append(devices, SwapDevice{
+			Name:     fields[0],
+			Type:     fields[1],
+			Size:     size * 1024,
+			Used:     used * 1024,
+			Priority: priority,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return devices, nil
+}
+
+// GetSwapTotal returns the node's total swap in bytes from the SwapTotal
+// line of /proc/meminfo (0 when no swap is configured)
+func (s *Scanner) GetSwapTotal() (int64, error) {
+	return s.readMeminfoBytes("SwapTotal")
+}
+
+// GetMemTotal returns node RAM in bytes from /proc/meminfo MemTotal
+func (s *Scanner) GetMemTotal() (int64, error) {
+	return s.readMeminfoBytes("MemTotal")
+}
+
+// readMeminfoBytes returns a kB field from /proc/meminfo in bytes
+func (s *Scanner) readMeminfoBytes(field string) (int64, error) {
+	path := filepath.Join(s.procPath, "meminfo")
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// Parse: SwapTotal:       8388604 kB
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != field+":" {
+			continue
+		}
+		kb, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse %s %q: %w", field, fields[1], err)
+		}
+		return kb * 1024, nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return 0, fmt.Errorf("%s not found in %s", field, path)
+}
+
+// GetSwappiness returns vm.swappiness from /proc/sys/vm/swappiness
+func (s *Scanner) GetSwappiness() (int64, error) {
+	path := filepath.Join(s.procPath, "sys", "vm", "swappiness")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	value, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return value, nil
+}
+
+// IsPodUID reports whether uid looks like a standard pod UID
+// (36 characters, 8-4-4-4-12 groups separated by dashes)
+func IsPodUID(uid string) bool {
+	if len(uid) != 36 {
+		return false
+	}
+	for i, r := range uid {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return false
+			}
+		default:
+			if r == '-' {
+				return false
+			}
+		}
+	}
+	return true
 }
 
 // ExtractPodUID extracts the pod UID from a cgroup path
@@ -250,13 +789,18 @@ func ExtractPodUID(cgroupPath string) string {
 
 		// Convert underscores to dashes (cgroup uses underscores)
 		uid = strings.ReplaceAll(uid, "_", "-")
+		if !IsPodUID(uid) {
+			// Still returned; callers fall back to the raw underscore form on lookup
+			klog.V(4).InfoS("Pod UID from cgroup path is not a standard UUID", "cgroupPath", cgroupPath, "uid", uid)
+		}
 		return uid
 	}
 	return ""
 }
 
 // ExtractQoS extracts the QoS class from a cgroup path
-// Returns "burstable", "besteffort", or "guaranteed"
+// Returns "burstable", "besteffort", "guaranteed", or "" if the path matches
+// no known pod cgroup structure
 func ExtractQoS(cgroupPath string) string {
 	if strings.Contains(cgroupPath, "kubepods-burstable") {
 		return "burstable"
@@ -264,9 +808,13 @@ func ExtractQoS(cgroupPath string) string {
 	if strings.Contains(cgroupPath, "kubepods-besteffort") {
 		return "besteffort"
 	}
-	// Guaranteed pods are directly under kubepods.slice without QoS subdirectory
-	if strings.Contains(cgroupPath, "kubepods.slice") {
-		return "guaranteed"
+	// Guaranteed pods are directly under kubepods.slice without QoS subdirectory,
+	// as kubepods-pod<uid>.slice; the kubepods slice alone or a stray cgroup
+	// beneath it is not a pod
+	for _, part := range strings.Split(cgroupPath, "/") {
+		if uid, ok := strings.CutPrefix(part, "kubepods-pod"); ok && strings.HasSuffix(uid, ".slice") && uid != ".slice" {
+			return "guaranteed"
+		}
 	}
 	return ""
 }
@@ -277,9 +825,10 @@ func IsBurstable(cgroupPath string) bool {
 }
 
 // ExtractContainerID extracts the container ID from a cgroup path
-// Input: .../cri-containerd-<id>.scope or .../crio-<id>.scope
+// Input: .../<prefix><id>.scope for any configured runtime prefix
+// (e.g. .../cri-containerd-<id>.scope or .../crio-<id>.scope)
 // Returns the container ID (e.g., "abc123...")
-func ExtractContainerID(cgroupPath string) string {
+func (s *Scanner) ExtractContainerID(cgroupPath string) string {
 	parts := strings.Split(cgroupPath, "/")
 	if len(parts) == 0 {
 		return ""
@@ -292,15 +841,12 @@ func ExtractContainerID(cgroupPath string) string {
 	}
 	scope = strings.TrimSuffix(scope, ".scope")
 
-	// Extract container ID based on runtime prefix
-	if strings.HasPrefix(scope, "cri-containerd-") {
-		return strings.TrimPrefix(scope, "cri-containerd-")
-	}
-	if strings.HasPrefix(scope, "crio-") {
-		return strings.TrimPrefix(scope, "crio-")
+	// Strip whichever runtime prefix matched
+	prefix := s.matchRuntimePrefix(scope)
+	if prefix == "" {
+		return ""
 	}
-
-	return ""
+	return strings.TrimPrefix(scope, prefix)
 }
 
 func readPSI(path string) (*PSI, error) {
@@ -360,6 +906,126 @@ func readPSI(path string) (*PSI, error) {
 	return psi, scanner.Err()
 }
 
+func readMemoryStat(path string) (*MemoryStat, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	stat := &MemoryStat{}
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		// Parse: <key> <value>
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+
+		var dest *int64
+		switch fields[0] {
+		case "swapcached":
+			dest = &stat.SwapCached
+		case "zswap":
+			dest = &stat.Zswap
+		case "zswapped":
+			dest = &stat.Zswapped
+		default:
+			continue
+		}
+
+		val, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			klog.V(4).InfoS("Failed to parse memory.stat value", "key", fields[0], "value", fields[1], "err", err)
+			continue
+		}
+		*dest = val
+	}
+
+	return stat, scanner.Err()
+}
+
+func readSwapEvents(path string) (*SwapEvents, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	events := &SwapEvents{}
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		// Parse: high 0 / max 12 / fail 3
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+
+		var dest *uint64
+		switch fields[0] {
+		case "high":
+			dest = &events.High
+		case "max":
+			dest = &events.Max
+		case "fail":
+			dest = &events.Fail
+		default:
+			continue
+		}
+
+		val, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			klog.V(4).InfoS("Failed to parse memory.swap.events value", "key", fields[0], "value", fields[1], "err", err)
+			continue
+		}
+		*dest = val
+	}
+
+	return events, scanner.Err()
+}
+
+func readMemoryEvents(path string) (*MemoryEvents, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	events := &MemoryEvents{}
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		// Parse: low 0 / high 42 / max 3 / oom 0 / oom_kill 0 / oom_group_kill 0
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+
+		var dest *uint64
+		switch fields[0] {
+		case "high":
+			dest = &events.High
+		case "max":
+			dest = &events.Max
+		case "oom_kill":
+			dest = &events.OOMKill
+		default:
+			continue
+		}
+
+		val, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			klog.V(4).InfoS("Failed to parse memory.events value", "key", fields[0], "value", fields[1], "err", err)
+			continue
+		}
+		*dest = val
+	}
+
+	return events, scanner.Err()
+}
+
 func readInt64File(path string) (int64, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -377,7 +1043,7 @@ func readMemoryMax(path string) (int64, error) {
 	content := strings.TrimSpace(string(data))
 	if content == "max" {
 		// Return a very large value for unlimited
-		return 1 << 62, nil // ~4 exabytes
+		return UnlimitedBytes, nil
 	}
 	return strconv.ParseInt(content, 10, 64)
 }
@@ -1,6 +1,8 @@
 package cgroup
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -269,6 +271,53 @@ func TestValidateEnvironment(t *testing.T) {
 		}
 	})
 
+	t.Run("container swap accounting readable", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		if err := os.WriteFile(filepath.Join(tmpDir, "cgroup.controllers"), []byte("memory cpu"), 0644); err != nil {
+			t.Fatalf("Failed to create cgroup.controllers: %v", err)
+		}
+		kubepodsPath := filepath.Join(tmpDir, "kubepods.slice")
+		containerPath := filepath.Join(kubepodsPath, "kubepods-burstable.slice/kubepods-burstable-pod123.slice/cri-containerd-abc123.scope")
+		if err := os.MkdirAll(containerPath, 0755); err != nil {
+			t.Fatalf("Failed to create container cgroup: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(kubepodsPath, "memory.swap.max"), []byte("max"), 0644); err != nil {
+			t.Fatalf("Failed to create memory.swap.max: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(containerPath, "memory.swap.current"), []byte("0"), 0644); err != nil {
+			t.Fatalf("Failed to create memory.swap.current: %v", err)
+		}
+
+		scanner := NewScanner(tmpDir)
+		if err := scanner.ValidateEnvironment(); err != nil {
+			t.Errorf("ValidateEnvironment() unexpected error: %v", err)
+		}
+	})
+
+	t.Run("container swap accounting missing", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		// memory.swap.max exists on kubepods.slice, but the container has no
+		// memory.swap.current (swapaccount=0)
+		if err := os.WriteFile(filepath.Join(tmpDir, "cgroup.controllers"), []byte("memory cpu"), 0644); err != nil {
+			t.Fatalf("Failed to create cgroup.controllers: %v", err)
+		}
+		kubepodsPath := filepath.Join(tmpDir, "kubepods.slice")
+		containerPath := filepath.Join(kubepodsPath, "kubepods-burstable.slice/kubepods-burstable-pod123.slice/cri-containerd-abc123.scope")
+		if err := os.MkdirAll(containerPath, 0755); err != nil {
+			t.Fatalf("Failed to create container cgroup: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(kubepodsPath, "memory.swap.max"), []byte("max"), 0644); err != nil {
+			t.Fatalf("Failed to create memory.swap.max: %v", err)
+		}
+
+		scanner := NewScanner(tmpDir)
+		if err := scanner.ValidateEnvironment(); err == nil {
+			t.Error("ValidateEnvironment() expected error when container memory.swap.current is missing")
+		}
+	})
+
 	t.Run("missing swap support", func(t *testing.T) {
 		tmpDir := t.TempDir()
 
@@ -325,7 +374,7 @@ func TestFindPodCgroups(t *testing.T) {
 
 		paths := []string{
 			"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/cri-containerd-abc123.scope",
-			"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/init.scope",          // unrecognized .scope
+			"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/misc.scope",          // unrecognized .scope
 			"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod456.slice/docker-def456.scope", // unrecognized .scope
 			"kubepods.slice/kubepods-burstable.slice/some-other-dir",                                      // not a .scope, ignored
 			"kubepods.slice/system.slice",                                                                 // not a .scope dir, ignored
@@ -352,6 +401,39 @@ func TestFindPodCgroups(t *testing.T) {
 		}
 	})
 
+	t.Run("mixed runtimes attribute each scope to its pod", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		// One pod per runtime, as on a node migrating between them
+		want := map[string]string{
+			"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podaaaa1111_2222_3333_4444_555566667777.slice/cri-containerd-abc123.scope": "aaaa1111-2222-3333-4444-555566667777",
+			"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podbbbb1111_2222_3333_4444_555566667777.slice/crio-def456.scope":           "bbbb1111-2222-3333-4444-555566667777",
+		}
+		for p := range want {
+			if err := os.MkdirAll(filepath.Join(tmpDir, p), 0755); err != nil {
+				t.Fatalf("Failed to create test directory: %v", err)
+			}
+		}
+
+		scanner := NewScanner(tmpDir)
+		scanner.DetectRuntimes()
+		result, err := scanner.FindPodCgroups()
+		if err != nil {
+			t.Fatalf("FindPodCgroups() error = %v", err)
+		}
+		if len(result.Cgroups) != len(want) {
+			t.Fatalf("FindPodCgroups() returned %d cgroups, want %d", len(result.Cgroups), len(want))
+		}
+		for _, cgroupPath := range result.Cgroups {
+			if got := ExtractPodUID(cgroupPath); got != want[cgroupPath] {
+				t.Errorf("ExtractPodUID(%s) = %q, want %q", cgroupPath, got, want[cgroupPath])
+			}
+			if got := scanner.ExtractContainerID(cgroupPath); got == "" {
+				t.Errorf("ExtractContainerID(%s) = \"\", want the runtime's container ID", cgroupPath)
+			}
+		}
+	})
+
 	t.Run("error when kubepods.slice missing", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		// Don't create kubepods.slice
@@ -397,6 +479,25 @@ func TestExtractPodUID(t *testing.T) {
 	}
 }
 
+func TestIsPodUID(t *testing.T) {
+	tests := []struct {
+		uid      string
+		expected bool
+	}{
+		{"12345678-1234-1234-1234-123456789abc", true},
+		{"abc-def-123", false},
+		{"12345678_1234_1234_1234_123456789abc", false},
+		{"123456781-234-1234-1234-123456789abc", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if result := IsPodUID(tt.uid); result != tt.expected {
+			t.Errorf("IsPodUID(%q) = %v, want %v", tt.uid, result, tt.expected)
+		}
+	}
+}
+
 func TestExtractQoS(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -418,6 +519,21 @@ func TestExtractQoS(t *testing.T) {
 			path:     "kubepods.slice/kubepods-pod123.slice/cri-containerd-abc.scope",
 			expected: "guaranteed",
 		},
+		{
+			name:     "root only",
+			path:     "kubepods.slice",
+			expected: "",
+		},
+		{
+			name:     "malformed",
+			path:     "kubepods.slice/stray.slice/cri-containerd-abc.scope",
+			expected: "",
+		},
+		{
+			name:     "pod slice without uid",
+			path:     "kubepods.slice/kubepods-pod.slice/cri-containerd-abc.scope",
+			expected: "",
+		},
 	}
 
 	for _, tt := range tests {
@@ -462,3 +578,658 @@ func TestIsBurstable(t *testing.T) {
 		})
 	}
 }
+
+func TestGetContainerMetrics_MemoryStat(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cgroupPath := "kubepods.slice/cri-containerd-abc123.scope"
+	fullPath := filepath.Join(tmpDir, cgroupPath)
+	if err := os.MkdirAll(fullPath, 0755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err)
+	}
+
+	files := map[string]string{
+		"memory.swap.current": "104857600",
+		"memory.swap.max":     "max",
+		"memory.current":      "268435456",
+		"memory.max":          "536870912",
+		"memory.pressure": `some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+full avg10=0.00 avg60=0.00 avg300=0.00 total=0`,
+		"memory.stat": `anon 1000
+file 2000
+zswap 4096
+zswapped 16384
+swapcached 8192
+`,
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(fullPath, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	scanner := NewScanner(tmpDir)
+	metrics, err := scanner.GetContainerMetrics(cgroupPath)
+	if err != nil {
+		t.Fatalf("GetContainerMetrics() error = %v", err)
+	}
+
+	if metrics.Stat.Zswap != 4096 {
+		t.Errorf("Stat.Zswap = %d, want 4096", metrics.Stat.Zswap)
+	}
+	if metrics.Stat.Zswapped != 16384 {
+		t.Errorf("Stat.Zswapped = %d, want 16384", metrics.Stat.Zswapped)
+	}
+	if metrics.Stat.SwapCached != 8192 {
+		t.Errorf("Stat.SwapCached = %d, want 8192", metrics.Stat.SwapCached)
+	}
+
+	// memory.stat is optional: removing it must not fail the read
+	if err := os.Remove(filepath.Join(fullPath, "memory.stat")); err != nil {
+		t.Fatalf("Failed to remove memory.stat: %v", err)
+	}
+	metrics, err = scanner.GetContainerMetrics(cgroupPath)
+	if err != nil {
+		t.Fatalf("GetContainerMetrics() without memory.stat error = %v", err)
+	}
+	if metrics.Stat != (MemoryStat{}) {
+		t.Errorf("Stat = %+v, want zero value", metrics.Stat)
+	}
+}
+
+func TestReadSwapEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.swap.events")
+	if err := os.WriteFile(path, []byte("high 1\nmax 12\nfail 3\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	events, err := readSwapEvents(path)
+	if err != nil {
+		t.Fatalf("readSwapEvents() error = %v", err)
+	}
+	if *events != (SwapEvents{High: 1, Max: 12, Fail: 3}) {
+		t.Errorf("readSwapEvents() = %+v, want {High:1 Max:12 Fail:3}", *events)
+	}
+
+	// Absent on older kernels
+	if _, err := readSwapEvents(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("readSwapEvents() expected error for missing file")
+	}
+}
+
+func TestExtractContainerID(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefixes []string
+		path     string
+		expected string
+	}{
+		{
+			name:     "containerd",
+			path:     "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/cri-containerd-abc123.scope",
+			expected: "abc123",
+		},
+		{
+			name:     "crio",
+			path:     "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/crio-def456.scope",
+			expected: "def456",
+		},
+		{
+			name:     "kata not configured",
+			path:     "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/kata-ghi789.scope",
+			expected: "",
+		},
+		{
+			name:     "kata configured",
+			prefixes: []string{"cri-containerd-", "crio-", "kata-"},
+			path:     "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/kata-ghi789.scope",
+			expected: "ghi789",
+		},
+		{
+			name:     "docker not configured",
+			path:     "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/docker-0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef.scope",
+			expected: "",
+		},
+		{
+			name:     "docker configured",
+			prefixes: []string{"cri-containerd-", "crio-", DockerRuntimePrefix},
+			path:     "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/docker-0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef.scope",
+			expected: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		},
+		{
+			name:     "not a scope",
+			path:     "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := NewScannerWithOptions(t.TempDir(), Options{RuntimePrefixes: tt.prefixes})
+			result := scanner.ExtractContainerID(tt.path)
+			if result != tt.expected {
+				t.Errorf("ExtractContainerID(%q) = %q, want %q", tt.path, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindPodCgroups_CustomRuntimePrefixes(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	paths := []string{
+		"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/cri-containerd-abc123.scope",
+		"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod456.slice/kata-def456.scope",
+	}
+	for _, p := range paths {
+		if err := os.MkdirAll(filepath.Join(tmpDir, p), 0755); err != nil {
+			t.Fatalf("Failed to create test directory: %v", err)
+		}
+	}
+
+	// Default prefixes: kata scope is unrecognized
+	result, err := NewScanner(tmpDir).FindPodCgroups()
+	if err != nil {
+		t.Fatalf("FindPodCgroups() error = %v", err)
+	}
+	if len(result.Cgroups) != 1 || len(result.Unrecognized) != 1 {
+		t.Errorf("default prefixes: got %d cgroups, %d unrecognized, want 1 and 1", len(result.Cgroups), len(result.Unrecognized))
+	}
+
+	// With kata- configured: both recognized
+	scanner := NewScannerWithOptions(tmpDir, Options{RuntimePrefixes: []string{"cri-containerd-", "kata-"}})
+	result, err = scanner.FindPodCgroups()
+	if err != nil {
+		t.Fatalf("FindPodCgroups() error = %v", err)
+	}
+	if len(result.Cgroups) != 2 || len(result.Unrecognized) != 0 {
+		t.Errorf("custom prefixes: got %d cgroups, %d unrecognized, want 2 and 0", len(result.Cgroups), len(result.Unrecognized))
+	}
+}
+
+func TestGetSwapIOStats_ProcPath(t *testing.T) {
+	procDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(procDir, "vmstat"), []byte("pswpin 7\npswpout 9\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	scanner := NewScannerWithOptions(t.TempDir(), Options{ProcPath: procDir})
+	stats, err := scanner.GetSwapIOStats()
+	if err != nil {
+		t.Fatalf("GetSwapIOStats() error = %v", err)
+	}
+	if stats.PswpIn != 7 || stats.PswpOut != 9 {
+		t.Errorf("GetSwapIOStats() = %+v, want PswpIn=7 PswpOut=9", *stats)
+	}
+}
+
+func TestGetSwapDevices(t *testing.T) {
+	procDir := t.TempDir()
+	content := `Filename				Type		Size		Used		Priority
+/dev/zram0                              partition	8388604		1024		100
+/var/swap/swapfile                      file		2097148		0		-2
+`
+	if err := os.WriteFile(filepath.Join(procDir, "swaps"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	scanner := NewScannerWithOptions(t.TempDir(), Options{ProcPath: procDir})
+	devices, err := scanner.GetSwapDevices()
+	if err != nil {
+		t.Fatalf("GetSwapDevices() error = %v", err)
+	}
+
+	if len(devices) != 2 {
+		t.Fatalf("GetSwapDevices() returned %d devices, want 2", len(devices))
+	}
+	if devices[0].Name != "/dev/zram0" || devices[0].Type != "partition" || devices[0].Size != 8388604*1024 || devices[0].Used != 1024*1024 || devices[0].Priority != 100 {
+		t.Errorf("devices[0] = %+v", devices[0])
+	}
+	if devices[1].Name != "/var/swap/swapfile" || devices[1].Type != "file" || devices[1].Priority != -2 {
+		t.Errorf("devices[1] = %+v", devices[1])
+	}
+}
+
+func TestGetSwapDevices_NoSwap(t *testing.T) {
+	procDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(procDir, "swaps"), []byte("Filename\t\t\t\tType\t\tSize\t\tUsed\t\tPriority\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	scanner := NewScannerWithOptions(t.TempDir(), Options{ProcPath: procDir})
+	devices, err := scanner.GetSwapDevices()
+	if err != nil {
+		t.Fatalf("GetSwapDevices() error = %v", err)
+	}
+	if len(devices) != 0 {
+		t.Errorf("GetSwapDevices() returned %d devices, want 0", len(devices))
+	}
+}
+
+func TestContainerMetrics_SwapPercent(t *testing.T) {
+	tests := []struct {
+		name     string
+		metrics  ContainerMetrics
+		expected float64
+	}{
+		{"memory limit", ContainerMetrics{SwapCurrent: 50, MemoryMax: 200, SwapMax: UnlimitedBytes}, 25},
+		{"unlimited memory falls back to swap.max", ContainerMetrics{SwapCurrent: 50, MemoryMax: UnlimitedBytes, SwapMax: 100}, 50},
+		{"zero memory.max falls back to swap.max", ContainerMetrics{SwapCurrent: 50, MemoryMax: 0, SwapMax: 100}, 50},
+		{"zero memory.max, no swap limit", ContainerMetrics{SwapCurrent: 50, MemoryMax: 0, SwapMax: UnlimitedBytes}, 0},
+		{"unlimited memory falls back to node memory", ContainerMetrics{SwapCurrent: 50, MemoryMax: UnlimitedBytes, SwapMax: UnlimitedBytes, NodeMemTotal: 200}, 25},
+		{"node memory takes precedence over swap.max", ContainerMetrics{SwapCurrent: 50, MemoryMax: UnlimitedBytes, SwapMax: 100, NodeMemTotal: 200}, 25},
+		{"memory limit ignores node memory", ContainerMetrics{SwapCurrent: 50, MemoryMax: 100, SwapMax: UnlimitedBytes, NodeMemTotal: 200}, 50},
+		{"both zero", ContainerMetrics{SwapCurrent: 50}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.metrics.SwapPercent(); got != tt.expected {
+				t.Errorf("SwapPercent() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetContainerMetrics_Pids(t *testing.T) {
+	tests := []struct {
+		name        string
+		pidsFiles   map[string]string
+		wantCurrent int64
+		wantMax     int64
+	}{
+		{"limited", map[string]string{"pids.current": "90", "pids.max": "100"}, 90, 100},
+		{"unlimited", map[string]string{"pids.current": "90", "pids.max": "max"}, 90, UnlimitedBytes},
+		{"pids controller disabled", nil, 0, UnlimitedBytes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			cgroupPath := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/cri-containerd-abc123.scope"
+			fullPath := filepath.Join(tmpDir, cgroupPath)
+			if err := os.MkdirAll(fullPath, 0755); err != nil {
+				t.Fatalf("Failed to create test directory: %v", err)
+			}
+
+			files := map[string]string{
+				"memory.swap.current": "0",
+				"memory.swap.max":     "max",
+				"memory.current":      "134217728",
+				"memory.max":          "536870912",
+				"memory.pressure":     "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0",
+			}
+			for name, content := range tt.pidsFiles {
+				files[name] = content
+			}
+			for name, content := range files {
+				if err := os.WriteFile(filepath.Join(fullPath, name), []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to write test file: %v", err)
+				}
+			}
+
+			metrics, err := NewScanner(tmpDir).GetContainerMetrics(cgroupPath)
+			if err != nil {
+				t.Fatalf("GetContainerMetrics() error = %v", err)
+			}
+			if metrics.PidsCurrent != tt.wantCurrent || metrics.PidsMax != tt.wantMax {
+				t.Errorf("pids = %d/%d, want %d/%d", metrics.PidsCurrent, metrics.PidsMax, tt.wantCurrent, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestContainerMetrics_PidsPercent(t *testing.T) {
+	tests := []struct {
+		name     string
+		metrics  ContainerMetrics
+		expected float64
+	}{
+		{"limited", ContainerMetrics{PidsCurrent: 90, PidsMax: 100}, 90},
+		{"unlimited", ContainerMetrics{PidsCurrent: 90, PidsMax: UnlimitedBytes}, 0},
+		{"unknown", ContainerMetrics{PidsCurrent: 90}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.metrics.PidsPercent(); got != tt.expected {
+				t.Errorf("PidsPercent() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetSwapTotal(t *testing.T) {
+	procDir := t.TempDir()
+	meminfo := "MemTotal:       16318412 kB\nMemFree:         1203348 kB\nSwapCached:         1024 kB\nSwapTotal:       8388604 kB\nSwapFree:        8388604 kB\n"
+	if err := os.WriteFile(filepath.Join(procDir, "meminfo"), []byte(meminfo), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	scanner := NewScannerWithOptions(t.TempDir(), Options{ProcPath: procDir})
+	total, err := scanner.GetSwapTotal()
+	if err != nil {
+		t.Fatalf("GetSwapTotal() error = %v", err)
+	}
+	if total != 8388604*1024 {
+		t.Errorf("GetSwapTotal() = %d, want %d", total, 8388604*1024)
+	}
+
+	// Missing SwapTotal line is an error rather than a silent 0
+	if err := os.WriteFile(filepath.Join(procDir, "meminfo"), []byte("MemTotal:       16318412 kB\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if _, err := scanner.GetSwapTotal(); err == nil {
+		t.Error("GetSwapTotal() expected error when SwapTotal is missing")
+	}
+}
+
+func TestReadMemoryEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.events")
+	if err := os.WriteFile(path, []byte("low 0\nhigh 42\nmax 3\noom 1\noom_kill 1\noom_group_kill 0\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	events, err := readMemoryEvents(path)
+	if err != nil {
+		t.Fatalf("readMemoryEvents() error = %v", err)
+	}
+	if *events != (MemoryEvents{High: 42, Max: 3, OOMKill: 1}) {
+		t.Errorf("readMemoryEvents() = %+v, want {High:42 Max:3 OOMKill:1}", *events)
+	}
+
+	if _, err := readMemoryEvents(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("readMemoryEvents() expected error for missing file")
+	}
+}
+
+func TestGetSwappiness(t *testing.T) {
+	procDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(procDir, "sys", "vm"), 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(procDir, "sys", "vm", "swappiness"), []byte("60\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	scanner := NewScannerWithOptions(t.TempDir(), Options{ProcPath: procDir})
+	swappiness, err := scanner.GetSwappiness()
+	if err != nil {
+		t.Fatalf("GetSwappiness() error = %v", err)
+	}
+	if swappiness != 60 {
+		t.Errorf("GetSwappiness() = %d, want 60", swappiness)
+	}
+}
+
+func TestGetContainerMetrics_UnlimitedBasis(t *testing.T) {
+	tmpDir := t.TempDir()
+	cgroupPath := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/cri-containerd-abc123.scope"
+	fullPath := filepath.Join(tmpDir, cgroupPath)
+	if err := os.MkdirAll(fullPath, 0755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err)
+	}
+	// 4GB swapped with no memory or swap limit
+	files := map[string]string{
+		"memory.swap.current": "4294967296",
+		"memory.swap.max":     "max",
+		"memory.current":      "1073741824",
+		"memory.max":          "max",
+		"memory.pressure":     "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(fullPath, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	// 16GB node
+	procDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(procDir, "meminfo"), []byte("MemTotal:       16777216 kB\nSwapTotal:       8388604 kB\n"), 0644); err != nil {
+		t.Fatalf("Failed to write meminfo: %v", err)
+	}
+
+	sentinel := NewScannerWithOptions(tmpDir, Options{ProcPath: procDir})
+	metrics, err := sentinel.GetContainerMetrics(cgroupPath)
+	if err != nil {
+		t.Fatalf("GetContainerMetrics() error = %v", err)
+	}
+	if got := metrics.SwapPercent(); got >= 0.001 {
+		t.Errorf("SwapPercent() with sentinel basis = %v, want ~0", got)
+	}
+
+	nodeMemory := NewScannerWithOptions(tmpDir, Options{ProcPath: procDir, UnlimitedBasis: UnlimitedBasisNodeMemory})
+	metrics, err = nodeMemory.GetContainerMetrics(cgroupPath)
+	if err != nil {
+		t.Fatalf("GetContainerMetrics() error = %v", err)
+	}
+	if metrics.NodeMemTotal != 16777216*1024 {
+		t.Errorf("NodeMemTotal = %d, want %d", metrics.NodeMemTotal, 16777216*1024)
+	}
+	if got := metrics.SwapPercent(); got != 25 {
+		t.Errorf("SwapPercent() with node-memory basis = %v, want 25", got)
+	}
+}
+
+func TestFindPodCgroupsContext_Canceled(t *testing.T) {
+	tmpDir := t.TempDir()
+	for i := 0; i < 20; i++ {
+		p := filepath.Join(tmpDir, "kubepods.slice/kubepods-burstable.slice",
+			"kubepods-burstable-pod"+string(rune('a'+i))+".slice", "cri-containerd-abc.scope")
+		if err := os.MkdirAll(p, 0755); err != nil {
+			t.Fatalf("Failed to create test directory: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	scanner := NewScanner(tmpDir)
+	result, err := scanner.FindPodCgroupsContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("FindPodCgroupsContext() error = %v, want context.Canceled", err)
+	}
+	if result != nil {
+		t.Errorf("FindPodCgroupsContext() returned %d cgroups after cancel, want nil result", len(result.Cgroups))
+	}
+}
+
+func TestFindPodCgroups_CustomKubepodsPath(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Kubelet started with --cgroup-root=/kubelet nests pods under kubelet.slice
+	scope := "kubelet.slice/kubelet-kubepods.slice/kubelet-kubepods-burstable.slice/kubelet-kubepods-burstable-pod123.slice/cri-containerd-abc123.scope"
+	if err := os.MkdirAll(filepath.Join(tmpDir, scope), 0755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err)
+	}
+
+	if _, err := NewScanner(tmpDir).FindPodCgroups(); err == nil {
+		t.Error("FindPodCgroups() with default path expected error when kubepods.slice is missing")
+	}
+
+	scanner := NewScannerWithOptions(tmpDir, Options{KubepodsPath: "kubelet.slice/kubelet-kubepods.slice"})
+	result, err := scanner.FindPodCgroups()
+	if err != nil {
+		t.Fatalf("FindPodCgroups() error = %v", err)
+	}
+	if len(result.Cgroups) != 1 || result.Cgroups[0] != scope {
+		t.Fatalf("FindPodCgroups() = %v, want [%s]", result.Cgroups, scope)
+	}
+	if uid := ExtractPodUID(result.Cgroups[0]); uid != "123" {
+		t.Errorf("ExtractPodUID() = %q, want 123", uid)
+	}
+	if !IsBurstable(result.Cgroups[0]) {
+		t.Error("IsBurstable() = false, want true under a custom kubepods path")
+	}
+}
+
+func TestFindPodCgroups_ExcludePaths(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	pod := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/cri-containerd-abc123.scope"
+	paths := []string{
+		pod,
+		"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/init.scope",
+		"kubepods.slice/system.slice/cri-containerd-def456.scope",
+	}
+	for _, p := range paths {
+		if err := os.MkdirAll(filepath.Join(tmpDir, p), 0755); err != nil {
+			t.Fatalf("Failed to create test directory: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name             string
+		excludes         []string
+		wantCgroups      int
+		wantUnrecognized int
+	}{
+		{"default excludes host cgroups", nil, 1, 0},
+		{"empty excludes nothing", []string{}, 2, 1},
+		{"custom", []string{"pod123"}, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := NewScannerWithOptions(tmpDir, Options{ExcludePaths: tt.excludes})
+			result, err := scanner.FindPodCgroups()
+			if err != nil {
+				t.Fatalf("FindPodCgroups() error = %v", err)
+			}
+			if len(result.Cgroups) != tt.wantCgroups {
+				t.Errorf("FindPodCgroups() returned %d cgroups, want %d: %v", len(result.Cgroups), tt.wantCgroups, result.Cgroups)
+			}
+			if len(result.Unrecognized) != tt.wantUnrecognized {
+				t.Errorf("FindPodCgroups() returned %d unrecognized, want %d: %v", len(result.Unrecognized), tt.wantUnrecognized, result.Unrecognized)
+			}
+		})
+	}
+
+	t.Run("pod cgroup parent under an excluded slice", func(t *testing.T) {
+		nested := t.TempDir()
+		scope := "system.slice/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/cri-containerd-abc123.scope"
+		if err := os.MkdirAll(filepath.Join(nested, scope), 0755); err != nil {
+			t.Fatalf("Failed to create test directory: %v", err)
+		}
+
+		scanner := NewScannerWithOptions(nested, Options{KubepodsPath: "system.slice/kubepods.slice"})
+		result, err := scanner.FindPodCgroups()
+		if err != nil {
+			t.Fatalf("FindPodCgroups() error = %v", err)
+		}
+		if len(result.Cgroups) != 1 {
+			t.Errorf("FindPodCgroups() returned %v, want [%s]", result.Cgroups, scope)
+		}
+	})
+}
+
+func TestGetContainerMetrics_InheritedMemoryMax(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	podSlice := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice"
+	cgroupPath := podSlice + "/cri-containerd-abc123.scope"
+	files := map[string]string{
+		"kubepods.slice/memory.max":                          "8589934592", // node allocatable, never used
+		podSlice + "/memory.max":                             "536870912",  // 512MB pod limit
+		cgroupPath + "/memory.max":                           "max",
+		cgroupPath + "/memory.swap.current":                  "104857600", // 100MB
+		cgroupPath + "/memory.swap.max":                      "max",
+		cgroupPath + "/memory.current":                       "268435456",
+		cgroupPath + "/memory.pressure":                      "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0",
+		"kubepods.slice/kubepods-burstable.slice/memory.max": "max",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, name)), 0755); err != nil {
+			t.Fatalf("Failed to create test directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	scanner := NewScanner(tmpDir)
+	metrics, err := scanner.GetContainerMetrics(cgroupPath)
+	if err != nil {
+		t.Fatalf("GetContainerMetrics() error = %v", err)
+	}
+	if metrics.MemoryMax != 536870912 {
+		t.Errorf("MemoryMax = %d, want the pod slice's 536870912", metrics.MemoryMax)
+	}
+
+	// Without a pod-level limit the QoS and kubepods slices are not consulted
+	if err := os.WriteFile(filepath.Join(tmpDir, podSlice, "memory.max"), []byte("max"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	metrics, err = scanner.GetContainerMetrics(cgroupPath)
+	if err != nil {
+		t.Fatalf("GetContainerMetrics() error = %v", err)
+	}
+	if metrics.MemoryMax != UnlimitedBytes {
+		t.Errorf("MemoryMax = %d, want UnlimitedBytes", metrics.MemoryMax)
+	}
+}
+
+func TestGetVmstatCounters(t *testing.T) {
+	procDir := t.TempDir()
+	content := "pswpin 7\npswpout 9\nzswpout 11\npgpgin bogus\n"
+	if err := os.WriteFile(filepath.Join(procDir, "vmstat"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write vmstat: %v", err)
+	}
+
+	scanner := NewScannerWithOptions(t.TempDir(), Options{ProcPath: procDir})
+	counters, err := scanner.GetVmstatCounters([]string{"zswpout", "pgpgin", "zswpin"})
+	if err != nil {
+		t.Fatalf("GetVmstatCounters() error = %v", err)
+	}
+	// Unparseable and missing counters are left out
+	if len(counters) != 1 || counters["zswpout"] != 11 {
+		t.Errorf("GetVmstatCounters() = %v, want map[zswpout:11]", counters)
+	}
+}
+
+func TestContainerMetrics_SwapOfLimitPercent(t *testing.T) {
+	tests := []struct {
+		name     string
+		metrics  ContainerMetrics
+		expected float64
+	}{
+		{"limited", ContainerMetrics{SwapCurrent: 80 << 20, SwapMax: 100 << 20, MemoryMax: 800 << 20}, 80},
+		{"unlimited", ContainerMetrics{SwapCurrent: 80 << 20, SwapMax: UnlimitedBytes, MemoryMax: 800 << 20}, 0},
+		{"unknown", ContainerMetrics{SwapCurrent: 80 << 20}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.metrics.SwapOfLimitPercent(); got != tt.expected {
+				t.Errorf("SwapOfLimitPercent() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestContainerMetrics_SwapPercentRounding(t *testing.T) {
+	tests := []struct {
+		name      string
+		swap, max int64
+		expected  float64
+	}{
+		{"rounds down below the half", 1_049, 100_000, 1.0}, // 1.049%
+		{"rounds up at the half", 1_050, 100_000, 1.1},      // 1.05%
+		{"smallest nonzero step", 50, 100_000, 0.1},         // 0.05%
+		{"below the smallest step", 49, 100_000, 0},         // 0.049%
+		{"exact", 250, 1_000, 25},                           // 25%
+		{"above 100", 3_333, 1_000, 333.3},                  // 333.3%
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := ContainerMetrics{SwapCurrent: tt.swap, MemoryMax: tt.max, SwapMax: tt.max}
+			if got := m.SwapPercent(); got != tt.expected {
+				t.Errorf("SwapPercent() = %v, want %v", got, tt.expected)
+			}
+			if got := m.SwapOfLimitPercent(); got != tt.expected {
+				t.Errorf("SwapOfLimitPercent() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
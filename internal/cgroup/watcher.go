@@ -0,0 +1,233 @@
+package cgroup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/klog/v2"
+)
+
+// Discovery modes for finding container cgroups
+const (
+	// DiscoveryWalk walks the kubepods.slice hierarchy on every call
+	DiscoveryWalk = "walk"
+	// DiscoveryWatch maintains a cached set updated by inotify events
+	DiscoveryWatch = "watch"
+)
+
+// cgroupWatcher maintains an in-memory set of container scope paths under
+// kubepods.slice, updated from fsnotify create/remove events.
+type cgroupWatcher struct {
+	scanner *Scanner
+	fsw     *fsnotify.Watcher
+
+	mu           sync.RWMutex
+	cgroups      map[string]struct{}
+	unrecognized map[string]struct{}
+}
+
+// StartWatch switches the scanner to watch-based discovery. It performs an
+// initial walk, watches kubepods.slice and its slice subdirectories for
+// changes, and periodically re-walks every resyncPeriod to reconcile missed
+// events. The watcher stops when ctx is cancelled.
+func (s *Scanner) StartWatch(ctx context.Context, resyncPeriod time.Duration) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create fsnotify watcher: %w", err)
+	}
+
+	w := &cgroupWatcher{
+		scanner: s,
+		fsw:     fsw,
+	}
+
+	if err := w.resync(); err != nil {
+		fsw.Close()
+		return err
+	}
+
+	s.watcher = w
+	go w.run(ctx, resyncPeriod)
+
+	return nil
+}
+
+// snapshot returns the cached cgroup set as a ScanResult
+func (w *cgroupWatcher) snapshot() *ScanResult {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	result := &ScanResult{}
+	for path := range w.cgroups {
+		result.Cgroups = append(result.Cgroups, path)
+	}
+	for path := range w.unrecognized {
+		result.Unrecognized = append(result.Unrecognized, path)
+	}
+	sort.Strings(result.Cgroups)
+	sort.Strings(result.Unrecognized)
+
+	return result
+}
+
+func (w *cgroupWatcher) run(ctx context.Context, resyncPeriod time.Duration) {
+	defer w.fsw.Close()
+
+	ticker := time.NewTicker(resyncPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.resync(); err != nil {
+				klog.ErrorS(err, "Cgroup watch resync failed")
+			}
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			w.handleEvent(event)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			// Overflow means events were dropped; a full walk restores consistency
+			klog.ErrorS(err, "Cgroup watcher error, resyncing")
+			if err := w.resync(); err != nil {
+				klog.ErrorS(err, "Cgroup watch resync failed")
+			}
+		}
+	}
+}
+
+func (w *cgroupWatcher) handleEvent(event fsnotify.Event) {
+	switch {
+	case event.Has(fsnotify.Create):
+		info, err := os.Stat(event.Name)
+		if err != nil || !info.IsDir() {
+			return
+		}
+		if strings.HasSuffix(info.Name(), ".slice") {
+			// New pod slice: watch it and pick up any scopes created before the watch was added
+			w.addTree(event.Name)
+			return
+		}
+		w.addScope(event.Name)
+	case event.Has(fsnotify.Remove), event.Has(fsnotify.Rename):
+		w.remove(event.Name)
+	}
+}
+
+// resync walks the hierarchy and replaces the cached set
+func (w *cgroupWatcher) resync() error {
+	result, err := w.scanner.walkPodCgroups(context.Background())
+	if err != nil {
+		return err
+	}
+
+	cgroups := make(map[string]struct{}, len(result.Cgroups))
+	for _, path := range result.Cgroups {
+		cgroups[path] = struct{}{}
+	}
+	unrecognized := make(map[string]struct{}, len(result.Unrecognized))
+	for _, path := range result.Unrecognized {
+		unrecognized[path] = struct{}{}
+	}
+
+	w.mu.Lock()
+	w.cgroups = cgroups
+	w.unrecognized = unrecognized
+	w.mu.Unlock()
+
+	// Ensure every slice directory is watched (Add is a no-op for existing watches)
+	w.watchSlices(w.scanner.kubepodsDir())
+
+	klog.V(4).InfoS("Cgroup watch resynced", "containerCgroups", len(cgroups))
+	return nil
+}
+
+// addTree watches a newly created slice and records any scopes already under it
+func (w *cgroupWatcher) addTree(root string) {
+	w.watchSlices(root)
+
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() && w.scanner.isExcluded(path) {
+			return filepath.SkipDir
+		}
+		if info.IsDir() && strings.HasSuffix(info.Name(), ".scope") {
+			w.addScope(path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+// watchSlices adds fsnotify watches on root and every .slice directory below it
+func (w *cgroupWatcher) watchSlices(root string) {
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		if path != root && (!strings.HasSuffix(info.Name(), ".slice") || w.scanner.isExcluded(path)) {
+			// Scopes and other leaf cgroups don't contain pods
+			return filepath.SkipDir
+		}
+		if err := w.fsw.Add(path); err != nil {
+			klog.V(4).InfoS("Failed to watch cgroup directory", "path", path, "err", err)
+		}
+		return nil
+	})
+}
+
+func (w *cgroupWatcher) addScope(fullPath string) {
+	name := filepath.Base(fullPath)
+	if !strings.HasSuffix(name, ".scope") || w.scanner.isExcluded(fullPath) {
+		return
+	}
+
+	relPath, err := filepath.Rel(w.scanner.cgroupRoot, fullPath)
+	if err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.scanner.isContainerScope(name) {
+		w.cgroups[relPath] = struct{}{}
+	} else {
+		w.unrecognized[relPath] = struct{}{}
+	}
+}
+
+// remove drops the path and anything below it from the cached set
+func (w *cgroupWatcher) remove(fullPath string) {
+	relPath, err := filepath.Rel(w.scanner.cgroupRoot, fullPath)
+	if err != nil {
+		return
+	}
+	prefix := relPath + "/"
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, set := range []map[string]struct{}{w.cgroups, w.unrecognized} {
+		for path := range set {
+			if path == relPath || strings.HasPrefix(path, prefix) {
+				delete(set, path)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package cgroup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitForCgroups polls FindPodCgroups until it returns want container cgroups
+func waitForCgroups(t *testing.T, scanner *Scanner, want int) *ScanResult {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		result, err := scanner.FindPodCgroups()
+		if err != nil {
+			t.Fatalf("FindPodCgroups() error = %v", err)
+		}
+		if len(result.Cgroups) == want {
+			return result
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("FindPodCgroups() returned %d cgroups, want %d: %v", len(result.Cgroups), want, result.Cgroups)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStartWatch(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	existing := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/cri-containerd-abc123.scope"
+	if err := os.MkdirAll(filepath.Join(tmpDir, existing), 0755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	scanner := NewScanner(tmpDir)
+	if err := scanner.StartWatch(ctx, time.Hour); err != nil {
+		t.Fatalf("StartWatch() error = %v", err)
+	}
+
+	// Initial walk populates the cache
+	waitForCgroups(t, scanner, 1)
+
+	// New scope in an existing pod slice
+	added := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/crio-def456.scope"
+	if err := os.MkdirAll(filepath.Join(tmpDir, added), 0755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err)
+	}
+	waitForCgroups(t, scanner, 2)
+
+	// New pod slice with its scope created in one go
+	newPod := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod456.slice/cri-containerd-ghi789.scope"
+	if err := os.MkdirAll(filepath.Join(tmpDir, newPod), 0755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err)
+	}
+	waitForCgroups(t, scanner, 3)
+
+	// Removing a pod slice drops its scopes
+	if err := os.RemoveAll(filepath.Join(tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod456.slice")); err != nil {
+		t.Fatalf("Failed to remove test directory: %v", err)
+	}
+	result := waitForCgroups(t, scanner, 2)
+	for _, path := range result.Cgroups {
+		if path == newPod {
+			t.Errorf("removed cgroup %s still present", newPod)
+		}
+	}
+}
+
+func TestStartWatch_ResyncReconcilesMissedEvents(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(tmpDir, "kubepods.slice"), 0755); err != nil {
+		t.Fatalf("Failed to create kubepods.slice: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	scanner := NewScanner(tmpDir)
+	if err := scanner.StartWatch(ctx, 50*time.Millisecond); err != nil {
+		t.Fatalf("StartWatch() error = %v", err)
+	}
+
+	// Simulate a missed event by injecting directly into the walk target
+	// and clearing the cache; the periodic resync must restore it.
+	path := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/cri-containerd-abc123.scope"
+	if err := os.MkdirAll(filepath.Join(tmpDir, path), 0755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err)
+	}
+	scanner.watcher.mu.Lock()
+	scanner.watcher.cgroups = map[string]struct{}{}
+	scanner.watcher.mu.Unlock()
+
+	waitForCgroups(t, scanner, 1)
+}
+
+func TestStartWatch_MissingKubepods(t *testing.T) {
+	scanner := NewScanner(t.TempDir())
+	if err := scanner.StartWatch(context.Background(), time.Minute); err == nil {
+		t.Error("StartWatch() expected error when kubepods.slice missing")
+	}
+}
@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"k8s.io/klog/v2"
+)
+
+// adaptPollInterval adjusts the poll interval after a reconcile pass:
+//   - rising pressure (more pods over threshold than the previous pass, or a
+//     higher swap-out rate than the previous pass): halve, down to MinPollInterval
+//   - calm (no pods over threshold and no swap-out): double, up to PollInterval
+//   - otherwise (steady pressure): keep the current interval
+//
+// Only swap-out counts as pressure; heavy swap-in is typical while a node
+// recovers and shouldn't speed up polling.
+func (c *Controller) adaptPollInterval(ioRate swapIORate, ioOK bool, prevOverThreshold int) {
+	prevOut := c.lastSwapOutRate
+	if ioOK {
+		c.lastSwapOutRate = ioRate.out
+	}
+
+	interval := c.pollInterval
+	switch {
+	case c.lastOverThreshold > prevOverThreshold || (ioOK && ioRate.out > prevOut):
+		interval /= 2
+	case c.lastOverThreshold == 0 && (!ioOK || ioRate.out == 0):
+		interval *= 2
+	}
+
+	interval = max(interval, c.config.MinPollInterval)
+	interval = min(interval, c.config.PollInterval)
+
+	if interval != c.pollInterval {
+		klog.V(3).InfoS("Adjusted poll interval", "from", c.pollInterval, "to", interval, "overThreshold", c.lastOverThreshold, "swapOutPagesPerSec", ioRate.out)
+		c.pollInterval = interval
+	}
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdaptPollInterval(t *testing.T) {
+	c := New(Config{
+		PollInterval:    time.Second,
+		MinPollInterval: 100 * time.Millisecond,
+		AdaptivePoll:    true,
+	})
+	c.pollInterval = time.Second
+
+	steps := []struct {
+		name          string
+		overThreshold int
+		ioRate        swapIORate
+		want          time.Duration
+	}{
+		{"swap I/O starts", 0, swapIORate{out: 100}, 500 * time.Millisecond},
+		{"pod crosses threshold", 1, swapIORate{out: 100}, 250 * time.Millisecond},
+		{"swap I/O rising", 1, swapIORate{out: 400}, 125 * time.Millisecond},
+		{"clamped at minimum", 2, swapIORate{out: 800}, 100 * time.Millisecond},
+		{"steady pressure holds", 2, swapIORate{out: 800}, 100 * time.Millisecond},
+		{"recovery swap-in is calm", 0, swapIORate{in: 5000}, 200 * time.Millisecond},
+		{"calm doubles again", 0, swapIORate{}, 400 * time.Millisecond},
+		{"calm doubles again", 0, swapIORate{}, 800 * time.Millisecond},
+		{"clamped at poll interval", 0, swapIORate{}, time.Second},
+	}
+
+	for _, step := range steps {
+		prev := c.lastOverThreshold
+		c.lastOverThreshold = step.overThreshold
+		c.adaptPollInterval(step.ioRate, true, prev)
+		if c.pollInterval != step.want {
+			t.Fatalf("%s: pollInterval = %s, want %s", step.name, c.pollInterval, step.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// namespaceBudgetWindow is the sliding window NamespaceKillBudgets counts kills over
+const namespaceBudgetWindow = time.Minute
+
+// ParseNamespaceKillBudgets parses a comma-separated list like
+// "batch=2,web=5/minute" into kills allowed per minute by namespace. The
+// "/minute" suffix is optional. An empty value means no budgets.
+func ParseNamespaceKillBudgets(value string) (map[string]int, error) {
+	budgets := make(map[string]int)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, fmt.Errorf("invalid namespace kill budget %q: expected namespace=count", part)
+		}
+
+		count, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(kv[1]), "/minute"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace kill budget %q: %w", part, err)
+		}
+		if count < 0 {
+			return nil, fmt.Errorf("invalid namespace kill budget %q: must be >= 0", part)
+		}
+
+		budgets[strings.TrimSpace(kv[0])] = count
+	}
+	return budgets, nil
+}
+
+// namespaceBudgetExhausted reports whether the pod's namespace has used its
+// kill budget within the last minute. Kills older than the window are
+// forgotten as a side effect.
+func (c *Controller) namespaceBudgetExhausted(namespace string, now time.Time) bool {
+	budget, ok := c.config.NamespaceKillBudgets[namespace]
+	if !ok {
+		return false
+	}
+
+	kills := c.namespaceKills[namespace]
+	for len(kills) > 0 && now.Sub(kills[0]) >= namespaceBudgetWindow {
+		kills = kills[1:]
+	}
+	if len(kills) == 0 {
+		delete(c.namespaceKills, namespace)
+	} else {
+		c.namespaceKills[namespace] = kills
+	}
+
+	if len(kills) < budget {
+		return false
+	}
+	klog.V(2).InfoS("Namespace kill budget exhausted, deferring kill", "namespace", namespace, "budget", budget, "window", namespaceBudgetWindow)
+	if c.config.Metrics != nil {
+		c.config.Metrics.KillsRateLimited.WithLabelValues(namespace).Inc()
+	}
+	return true
+}
+
+// recordNamespaceKill charges a kill against its namespace's budget, if it has one
+func (c *Controller) recordNamespaceKill(namespace string, now time.Time) {
+	if _, ok := c.config.NamespaceKillBudgets[namespace]; ok {
+		c.namespaceKills[namespace] = append(c.namespaceKills[namespace], now)
+	}
+}
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rophy/kube-soomkiller/internal/cgroup"
+	"github.com/rophy/kube-soomkiller/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseNamespaceKillBudgets(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    map[string]int
+		wantErr bool
+	}{
+		{"", map[string]int{}, false},
+		{"batch=2", map[string]int{"batch": 2}, false},
+		{" batch = 2 , web=5/minute ", map[string]int{"batch": 2, "web": 5}, false},
+		{"batch=0", map[string]int{"batch": 0}, false},
+		{"batch", nil, true},
+		{"=2", nil, true},
+		{"batch=two", nil, true},
+		{"batch=-1", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseNamespaceKillBudgets(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseNamespaceKillBudgets(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseNamespaceKillBudgets(%q) = %v, want %v", tt.value, got, tt.want)
+			continue
+		}
+		for ns, count := range tt.want {
+			if got[ns] != count {
+				t.Errorf("ParseNamespaceKillBudgets(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestNamespaceBudgetExhausted_SlidingWindow(t *testing.T) {
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		NamespaceKillBudgets: map[string]int{"batch": 2},
+		Metrics:              m,
+	})
+	start := time.Now()
+
+	c.recordNamespaceKill("batch", start)
+	c.recordNamespaceKill("batch", start.Add(20*time.Second))
+	c.recordNamespaceKill("web", start)
+
+	if c.namespaceBudgetExhausted("web", start) {
+		t.Error("namespace without a budget was limited")
+	}
+	if !c.namespaceBudgetExhausted("batch", start.Add(30*time.Second)) {
+		t.Error("batch with 2 kills in the last minute was not limited")
+	}
+	// The first kill leaves the window, freeing one slot
+	if c.namespaceBudgetExhausted("batch", start.Add(time.Minute)) {
+		t.Error("batch was still limited after its oldest kill left the window")
+	}
+	if got := testutil.ToFloat64(m.KillsRateLimited.WithLabelValues("batch")); got != 1 {
+		t.Errorf("KillsRateLimited{batch} = %v, want 1", got)
+	}
+}
+
+func TestFindAndKillOverThreshold_NamespaceKillBudget(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	pods := []*corev1.Pod{
+		createPodWithUID("batch-a", "batch", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		createPodWithUID("batch-b", "batch", "test-node", "bbbb1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		createPodWithUID("web", "web", "test-node", "cccc1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+	}
+	for _, pod := range pods {
+		uid := strings.ReplaceAll(string(pod.UID), "-", "_")
+		createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+uid+".slice/cri-containerd-"+uid[:4]+".scope", 100<<20, 512<<20)
+	}
+
+	fakeClient := fake.NewSimpleClientset(pods[0], pods[1], pods[2])
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		SwapThresholdPercent: 1.0,
+		NamespaceKillBudgets: map[string]int{"batch": 1},
+		K8sClient:            fakeClient,
+		CgroupScanner:        cgroup.NewScanner(tmpDir),
+		PodInformer:          newTestPodInformer(t, pods...),
+		Metrics:              m,
+	})
+
+	if err := c.findAndKillOverThreshold(context.Background()); err != nil {
+		t.Fatalf("findAndKillOverThreshold() error = %v", err)
+	}
+
+	remaining := 0
+	for _, pod := range pods {
+		if _, err := fakeClient.CoreV1().Pods(pod.Namespace).Get(context.Background(), pod.Name, metav1.GetOptions{}); err == nil {
+			remaining++
+			if pod.Namespace != "batch" {
+				t.Errorf("pod %s/%s without a namespace budget was not killed", pod.Namespace, pod.Name)
+			}
+		}
+	}
+	if remaining != 1 {
+		t.Errorf("%d pods remain, want one batch pod deferred by its budget", remaining)
+	}
+	if got := testutil.ToFloat64(m.PodsSkippedTotal.WithLabelValues(skipReasonNamespaceBudget)); got != 1 {
+		t.Errorf("PodsSkippedTotal{namespace_budget} = %v, want 1", got)
+	}
+}
+
+func TestFindAndKillOverThreshold_NamespaceKillBudgetDryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	pods := []*corev1.Pod{
+		createPodWithUID("batch-a", "batch", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		createPodWithUID("batch-b", "batch", "test-node", "bbbb1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+	}
+	for _, pod := range pods {
+		uid := strings.ReplaceAll(string(pod.UID), "-", "_")
+		createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+uid+".slice/cri-containerd-"+uid[:4]+".scope", 100<<20, 512<<20)
+	}
+
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		SwapThresholdPercent: 1.0,
+		DryRun:               true,
+		NamespaceKillBudgets: map[string]int{"batch": 1},
+		K8sClient:            fake.NewSimpleClientset(pods[0], pods[1]),
+		CgroupScanner:        cgroup.NewScanner(tmpDir),
+		PodInformer:          newTestPodInformer(t, pods...),
+		Metrics:              m,
+	})
+
+	if err := c.findAndKillOverThreshold(context.Background()); err != nil {
+		t.Fatalf("findAndKillOverThreshold() error = %v", err)
+	}
+
+	// Nothing was killed, so nothing is charged: both pods are reported as would-kill
+	if got := testutil.ToFloat64(m.PodsWouldKill); got != 2 {
+		t.Errorf("PodsWouldKill = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.PodsSkippedTotal.WithLabelValues(skipReasonNamespaceBudget)); got != 0 {
+		t.Errorf("PodsSkippedTotal{namespace_budget} = %v, want 0 under dry-run", got)
+	}
+	if got := len(c.namespaceKills["batch"]); got != 0 {
+		t.Errorf("batch budget charged %d kills under dry-run, want 0", got)
+	}
+}
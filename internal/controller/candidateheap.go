@@ -0,0 +1,82 @@
+package controller
+
+import "container/heap"
+
+// candidateHeap is a min-heap of pod candidates ordered by swap, least swap
+// at the root, so a scan capped at N pods can evict the least-swapping one
+// in O(log N) when a worse pod shows up. index maps each UID to its heap
+// position so aggregated containers can re-sift their pod.
+type candidateHeap struct {
+	items []*PodCandidate
+	index map[string]int
+}
+
+func newCandidateHeap() *candidateHeap {
+	return &candidateHeap{index: make(map[string]int)}
+}
+
+// lessSwap orders candidates by swap percent, then swap bytes, then UID so
+// eviction is deterministic
+func lessSwap(a, b *PodCandidate) bool {
+	if a.SwapPercent != b.SwapPercent {
+		return a.SwapPercent < b.SwapPercent
+	}
+	if a.SwapBytes != b.SwapBytes {
+		return a.SwapBytes < b.SwapBytes
+	}
+	return a.UID > b.UID
+}
+
+func (h *candidateHeap) Len() int           { return len(h.items) }
+func (h *candidateHeap) Less(i, j int) bool { return lessSwap(h.items[i], h.items[j]) }
+
+func (h *candidateHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.index[h.items[i].UID] = i
+	h.index[h.items[j].UID] = j
+}
+
+func (h *candidateHeap) Push(x interface{}) {
+	cand := x.(*PodCandidate)
+	h.index[cand.UID] = len(h.items)
+	h.items = append(h.items, cand)
+}
+
+func (h *candidateHeap) Pop() interface{} {
+	last := h.items[len(h.items)-1]
+	h.items[len(h.items)-1] = nil
+	h.items = h.items[:len(h.items)-1]
+	delete(h.index, last.UID)
+	return last
+}
+
+// get returns the retained candidate for uid, or nil
+func (h *candidateHeap) get(uid string) *PodCandidate {
+	if i, ok := h.index[uid]; ok {
+		return h.items[i]
+	}
+	return nil
+}
+
+// fixed restores heap order after uid's candidate was updated in place
+func (h *candidateHeap) fixed(uid string) {
+	if i, ok := h.index[uid]; ok {
+		heap.Fix(h, i)
+	}
+}
+
+// offer adds cand, evicting the least-swapping candidate if that keeps the
+// heap within limit (0 = unlimited). It returns the candidate dropped: the
+// evicted one, cand itself if it swaps less than all of them, or nil.
+func (h *candidateHeap) offer(cand *PodCandidate, limit int) *PodCandidate {
+	if limit <= 0 || h.Len() < limit {
+		heap.Push(h, cand)
+		return nil
+	}
+	if !lessSwap(h.items[0], cand) {
+		return cand
+	}
+	evicted := heap.Pop(h).(*PodCandidate)
+	heap.Push(h, cand)
+	return evicted
+}
@@ -2,13 +2,22 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"regexp"
 	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/rophy/kube-soomkiller/internal/audit"
 	"github.com/rophy/kube-soomkiller/internal/cgroup"
+	"github.com/rophy/kube-soomkiller/internal/metrics"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
@@ -16,15 +25,58 @@ import (
 
 // Config holds controller configuration
 type Config struct {
-	NodeName             string
-	PollInterval         time.Duration
-	SwapThresholdPercent float64 // Kill pods with swap > this % of memory.max
-	DryRun               bool
-	ProtectedNamespaces  []string // namespaces to never kill pods from
-	K8sClient            kubernetes.Interface
-	CgroupScanner        *cgroup.Scanner
-	EventRecorder        record.EventRecorder // optional, for emitting Kubernetes events
-	PodInformer          *PodInformer         // node-scoped pod cache
+	NodeName              string
+	PollInterval          time.Duration
+	ShutdownTimeout       time.Duration // max wait for an in-flight reconcile on shutdown
+	ScanTimeout           time.Duration // abandon a cgroup scan that takes longer than this (0 disables)
+	StartupGracePeriod    time.Duration // after Run starts, scan and report but never delete for this long
+	PauseRefresh          time.Duration // how often to re-read the node's PauseAnnotation (0 disables the check)
+	SwapThresholdPercent  float64       // Kill pods with swap > this % of memory.max
+	WarnThresholdPercent  float64       // Emit SoomkillWarning events for pods with swap > this % (0 disables)
+	WarnInterval          time.Duration // Minimum time between warnings for the same pod
+	SwapGrowthThreshold   float64       // Kill pods with swap growth > this many bytes/sec (0 disables)
+	KillOnSwapLimitEvents bool          // Kill pods whose memory.swap.events max/fail counters increased
+	KillOnMemoryHigh      bool          // Kill pods whose memory.events high counter increased
+	KillTopN              int           // Delete at most this many pods per reconcile, highest score first (0 = unlimited)
+	MaxCandidates         int           // Keep at most this many swapping pods per scan, highest swap first (0 = unlimited)
+	PidsThresholdPercent  float64       // Also kill pods with pids.current > this % of pids.max (0 disables)
+	NodeFractionThreshold float64       // Also kill pods whose swap bytes > this fraction of node SwapTotal (0 disables)
+	AdaptivePoll          bool          // Shrink the poll interval toward MinPollInterval while pressure rises
+	MinPollInterval       time.Duration // Fastest poll interval in adaptive mode
+	ScanOnlyOnSwapIO      bool          // Skip the cgroup scan when there was no swap I/O and nothing over threshold last pass
+	DeferOnActiveReclaim  bool          // Hold kills for one pass while node reclaim (pgsteal) exceeds ReclaimThreshold
+	ReclaimThreshold      float64       // Reclaimed pages/sec considered active reclaim (0 = DefaultReclaimThreshold)
+	SwapIOCounters        []string      // /proc/vmstat counters summed into the swap I/O rate (empty = DefaultSwapIOCounters)
+	TaintOnPressure       bool          // Taint the node NoSchedule while node swap stays above SwapActivationPercent
+	SwapActivationPercent float64       // Node swap used % (of total swap) considered pressure
+	TaintAfter            time.Duration // How long pressure must persist before tainting
+	EmergencySwapPercent  float64       // Node swap used % above which EmergencyGracePeriod caps pod grace periods (0 disables)
+	EmergencyGracePeriod  time.Duration // Grace period used for kills while node swap is above EmergencySwapPercent
+	EscalationDelay       time.Duration // Warn first and only kill pods still over threshold after this long (0 kills immediately)
+	ExcludeNotReadyAfter  time.Duration // Skip pods whose Ready condition has been False at least this long (0 disables)
+	DryRun                bool
+	DryRunNamespaces      []string // namespaces observed but never enforced, even when DryRun is false
+	ProtectedNamespaces   []string // namespaces to never kill pods from
+	SkipUnmanaged         bool     // Skip pods with no controller owner (they would not be recreated)
+	SkipOwnerKinds        []string // Skip pods owned by these kinds (e.g. Job)
+	ProtectDaemonSetPods  bool     // Skip DaemonSet pods (they are rescheduled onto the same node)
+	ExcludeConditions     []string // Skip pods with any of these condition types True (e.g. DisruptionTarget)
+	IgnoreContainers      []string // Container names excluded from per-pod swap aggregation (resolved via PodInformer)
+	AnnotateOwner         bool     // Annotate the pod's ReplicaSet/StatefulSet with LastKillAnnotation before deleting
+	K8sClient             kubernetes.Interface
+	KillAction            KillAction // how pods are killed (nil = DeleteAction using K8sClient)
+	CgroupScanner         *cgroup.Scanner
+	EventRecorder         record.EventRecorder // optional, for emitting Kubernetes events
+	EventReason           string               // reason on kill events (empty = DefaultEventReason)
+	PodInformer           *PodInformer         // node-scoped pod cache
+	Metrics               *metrics.Metrics     // optional, for reconcile instrumentation
+	AuditLog              *audit.Logger        // optional, durable record of kill decisions
+	ScoreWeights          ScoreWeights         // kill ordering weights (zero value = DefaultScoreWeights)
+	ScoreExpr             *ScoreExpr           // kill ordering expression, replacing ScoreWeights (nil = use ScoreWeights)
+	NamespaceKillBudgets  map[string]int       // kills allowed per minute by namespace; other namespaces are unlimited
+	SortBy                string               // kill ordering: SortByScore (empty = SortByScore) or SortByFootprint
+	DecisionSink          DecisionSink         // optional, receives every per-pod reconcile decision (for tests)
+	ProtectSelector       labels.Selector      // never kill pods whose labels match, in addition to ProtectedNamespaces (nil = none)
 }
 
 // Controller monitors swap pressure and terminates pods when necessary
@@ -33,14 +85,184 @@ type Controller struct {
 
 	// Protected namespaces (precomputed as map for O(1) lookup)
 	protectedNamespaces map[string]bool
+
+	// Dry-run namespaces (precomputed as map for O(1) lookup)
+	dryRunNamespaces map[string]bool
+
+	// Owner kinds whose pods are never killed (precomputed as map for O(1) lookup)
+	skipOwnerKinds map[string]bool
+
+	// Container names excluded from swap aggregation (precomputed as map for O(1) lookup)
+	ignoreContainers map[string]bool
+
+	// Pod condition types that exclude a pod from kills (precomputed as map for O(1) lookup)
+	excludeConditions map[corev1.PodConditionType]bool
+
+	// When Run started, for the startup grace period (zero for RunOnce)
+	startedAt time.Time
+
+	// Unix nanoseconds of the last successful reconcile (0 until Run starts)
+	lastReconcileTime atomic.Int64
+
+	// Set while a ScanTimeout-bounded scan goroutine is running, including
+	// one abandoned on a hung read, so at most one is ever in flight for the
+	// reconcile loop and one for ListCandidates
+	scanInFlight atomic.Bool
+	listInFlight atomic.Bool
+
+	// Previous swap sample per pod UID, for growth rate calculation.
+	// Only touched from the reconcile loop; entries for pods no longer
+	// using swap are pruned every reconcile.
+	swapHistory map[string]swapSample
+
+	// Warning state per pod UID for pods in the warn band. Only touched
+	// from the reconcile loop.
+	warnings map[string]warnState
+
+	// When each pod UID was first seen over threshold, for the escalation
+	// delay. Only touched from the reconcile loop; pruned every reconcile.
+	overSince map[string]time.Time
+
+	// Node swap pressure taint state. Only touched from the reconcile loop.
+	pressureSince time.Time // start of the current pressure episode (zero when clear)
+	nodeTainted   bool      // whether the taint is believed to be on the node
+	taintSynced   bool      // false until the node's taint has been checked once
+
+	// Previous /proc/vmstat sample and over-threshold count, for skipping
+	// idle scans. Only touched from the reconcile loop.
+	lastSwapIO        map[string]uint64
+	lastSwapIOAt      time.Time
+	lastOverThreshold int
+
+	// Previous reclaim counter sample, whether reclaim is active this pass,
+	// and whether kills were already deferred in the current reclaim
+	// episode. Only touched from the reconcile loop.
+	lastReclaim     map[string]uint64
+	lastReclaimAt   time.Time
+	reclaimActive   bool
+	reclaimDeferred bool
+
+	// Whether the node's PauseAnnotation holds kills, and when it was last
+	// read. Only touched from the reconcile loop.
+	paused         bool
+	pauseCheckedAt time.Time
+
+	// Whether the last scan hit MaxCandidates, so the warning is logged once
+	// per episode rather than every poll. Only touched from the reconcile loop.
+	candidatesCapped bool
+
+	// Clock for reconcile decisions (time.Now when nil); replay sets it to
+	// the snapshot capture time
+	now func() time.Time
+
+	// Labels of the LastKillInfo series, so the next kill can replace it.
+	// Only touched from the reconcile loop.
+	lastKilled types.NamespacedName
+
+	// Pod UIDs already counted in PodsWouldKill, so a dry-run pod that stays
+	// over threshold counts once, as its kill would. Only touched from the
+	// reconcile loop; entries are dropped when the pod is deleted.
+	wouldKill map[string]bool
+
+	// Recent kill times per namespace with a NamespaceKillBudgets entry,
+	// oldest first. Only touched from the reconcile loop.
+	namespaceKills map[string][]time.Time
+
+	// Kills awaiting their pod's removal from the informer cache (delete
+	// call time by pod UID), and pods deleted since the last reconcile.
+	// Written by the informer's delete handler, so guarded by deletedMu.
+	deletedMu    sync.Mutex
+	pendingKills map[string]time.Time
+	deletedUIDs  []string
+
+	// Current adaptive poll interval and the swap-out rate it was last
+	// adjusted for. Written by reconcile, read by Run after it completes.
+	pollInterval    time.Duration
+	lastSwapOutRate float64
+}
+
+// clock returns the current time for reconcile decisions
+func (c *Controller) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// swapSample is a pod's swap usage at a point in time
+type swapSample struct {
+	bytes       int64
+	limitEvents uint64
+	highEvents  uint64
+	at          time.Time
+
+	// Labels the growth metric was emitted with, so it can be deleted on prune
+	namespace string
+	name      string
 }
 
+// Reasons an over-threshold pod was not killed (soomkiller_pods_skipped_total)
+const (
+	skipReasonNotInCache         = "not_in_cache"
+	skipReasonTerminating        = "terminating"
+	skipReasonProtectedNamespace = "protected_namespace"
+	skipReasonProtectLabel       = "protect_label"
+	skipReasonUnmanaged          = "unmanaged"
+	skipReasonOwnerKind          = "owner_kind"
+	skipReasonDaemonSet          = "daemonset"
+	skipReasonKillLimit          = "kill_limit"
+	skipReasonEscalation         = "escalation_pending"
+	skipReasonPodReplaced        = "pod_replaced"
+	skipReasonCondition          = "condition"
+	skipReasonNamespaceBudget    = "namespace_budget"
+	skipReasonActiveReclaim      = "active_reclaim"
+)
+
+// DefaultEventReason is the reason on events emitted for killed pods
+const DefaultEventReason = "Soomkilled"
+
+// eventReasonPattern matches a single CamelCase token, as Kubernetes expects for event reasons
+var eventReasonPattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+
+// ValidateEventReason checks that reason is a single CamelCase token
+func ValidateEventReason(reason string) error {
+	if !eventReasonPattern.MatchString(reason) {
+		return fmt.Errorf("event reason %q must be a single CamelCase token (e.g. %s)", reason, DefaultEventReason)
+	}
+	return nil
+}
+
+// staleReconcileFactor is how many poll intervals may pass without a
+// successful reconcile before the controller reports itself unhealthy
+const staleReconcileFactor = 3
+
 // PodCandidate represents a pod that may be terminated
 type PodCandidate struct {
 	UID         string  // Pod UID from cgroup path
 	Namespace   string  // Populated from informer cache
 	Name        string  // Populated from informer cache
+	SwapBytes   int64   // Total swap usage across all containers
 	SwapPercent float64 // Max swap percentage across all containers
+	MemoryBytes int64   // Total memory.current across all containers (excludes swap)
+
+	PSIFullAvg10 float64 // Max memory.pressure full avg10 across all containers
+	Score        float64 // Composite kill-ordering score (see ScoreWeights and ScoreExpr)
+
+	SwapGrowthRate float64 // Swap bytes/sec since previous reconcile (0 if unknown)
+
+	SwapLimitEvents      uint64 // Sum of memory.swap.events max+fail across containers
+	SwapLimitEventsDelta uint64 // Increase in SwapLimitEvents since previous reconcile
+
+	MemoryHighEvents      uint64 // Sum of memory.events high across containers
+	MemoryHighEventsDelta uint64 // Increase in MemoryHighEvents since previous reconcile
+
+	PidsPercent float64 // Max pids.current as a percentage of pids.max across containers
+
+	SwapNodeFraction float64 // SwapBytes as a fraction of node SwapTotal (0 if unknown)
+
+	SwapOfLimitPercent float64 // Max swap usage as a percentage of memory.swap.max across containers (0 if unlimited)
+
+	GracePeriodSeconds *int64 // Grace period override for the kill (nil = the pod's own)
 }
 
 // New creates a new controller
@@ -51,38 +273,171 @@ func New(config Config) *Controller {
 		protectedNS[ns] = true
 	}
 
-	return &Controller{
+	dryRunNS := make(map[string]bool)
+	for _, ns := range config.DryRunNamespaces {
+		dryRunNS[ns] = true
+	}
+
+	skipKinds := make(map[string]bool)
+	for _, kind := range config.SkipOwnerKinds {
+		skipKinds[kind] = true
+	}
+
+	ignored := make(map[string]bool)
+	for _, name := range config.IgnoreContainers {
+		ignored[name] = true
+	}
+
+	excludeConds := make(map[corev1.PodConditionType]bool)
+	for _, cond := range config.ExcludeConditions {
+		excludeConds[corev1.PodConditionType(cond)] = true
+	}
+
+	c := &Controller{
 		config:              config,
 		protectedNamespaces: protectedNS,
+		dryRunNamespaces:    dryRunNS,
+		skipOwnerKinds:      skipKinds,
+		ignoreContainers:    ignored,
+		excludeConditions:   excludeConds,
+		warnings:            make(map[string]warnState),
+		overSince:           make(map[string]time.Time),
+		pendingKills:        make(map[string]time.Time),
+		wouldKill:           make(map[string]bool),
+		namespaceKills:      make(map[string][]time.Time),
 	}
+	if config.PodInformer != nil {
+		config.PodInformer.OnPodDeleted(c.podDeleted)
+	}
+	return c
+}
+
+// RunOnce performs the startup check and a single reconcile pass, for
+// diagnosing what the controller sees without starting the loop
+func (c *Controller) RunOnce(ctx context.Context) error {
+	klog.InfoS("Running single reconcile pass", "thresholdPercent", c.config.SwapThresholdPercent, "dryRun", c.config.DryRun)
+	c.checkCgroupsAtStartup()
+	return c.reconcile(ctx)
 }
 
 // Run starts the controller main loop
 func (c *Controller) Run(ctx context.Context) error {
+	c.startedAt = time.Now()
 	klog.InfoS("Controller started", "pollInterval", c.config.PollInterval)
+	if c.config.StartupGracePeriod > 0 {
+		klog.InfoS("Startup grace period configured, no pods will be deleted until it ends", "gracePeriod", c.config.StartupGracePeriod)
+	}
+	if c.config.AdaptivePoll {
+		klog.InfoS("Adaptive poll interval enabled", "minPollInterval", c.config.MinPollInterval, "maxPollInterval", c.config.PollInterval)
+	}
 	klog.InfoS("Configured swap threshold", "thresholdPercent", c.config.SwapThresholdPercent)
+	if c.config.NodeFractionThreshold > 0 {
+		klog.InfoS("Configured node swap fraction threshold", "swapNodeFractionThreshold", c.config.NodeFractionThreshold)
+	}
+	if c.config.PidsThresholdPercent > 0 {
+		klog.InfoS("Configured PID threshold", "pidsThresholdPercent", c.config.PidsThresholdPercent)
+	}
+	if c.config.WarnThresholdPercent > 0 {
+		klog.InfoS("Configured swap warning threshold", "warnThresholdPercent", c.config.WarnThresholdPercent, "warnInterval", c.config.WarnInterval)
+	}
 	if len(c.config.ProtectedNamespaces) > 0 {
 		klog.InfoS("Protected namespaces configured", "namespaces", c.config.ProtectedNamespaces)
 	}
+	if len(c.config.DryRunNamespaces) > 0 && !c.config.DryRun {
+		klog.InfoS("Dry-run namespaces configured", "namespaces", c.config.DryRunNamespaces)
+	}
+	if c.config.EmergencySwapPercent > 0 {
+		klog.InfoS("Emergency grace period configured", "emergencySwapPercent", c.config.EmergencySwapPercent, "gracePeriod", c.config.EmergencyGracePeriod)
+	}
+	if c.config.TaintOnPressure {
+		klog.InfoS("Node taint on swap pressure enabled", "taint", SwapPressureTaintKey, "activationPercent", c.config.SwapActivationPercent, "taintAfter", c.config.TaintAfter)
+	}
+	if c.config.SkipUnmanaged || len(c.config.SkipOwnerKinds) > 0 || c.config.ProtectDaemonSetPods {
+		klog.InfoS("Owner filters configured", "skipUnmanaged", c.config.SkipUnmanaged, "skipOwnerKinds", c.config.SkipOwnerKinds, "protectDaemonSetPods", c.config.ProtectDaemonSetPods)
+	}
+	if c.config.KillTopN > 0 {
+		klog.InfoS("Kill limit per pass configured", "killTopN", c.config.KillTopN)
+	}
+	if c.config.ScoreExpr != nil {
+		klog.InfoS("Kill ordering score expression configured", "scoreExpr", c.config.ScoreExpr.String())
+	}
+	if c.config.PauseRefresh > 0 {
+		klog.InfoS("Node pause annotation check enabled", "annotation", PauseAnnotation, "refresh", c.config.PauseRefresh)
+	}
+	if c.config.DeferOnActiveReclaim {
+		klog.InfoS("Kill deferral on active reclaim enabled", "counters", ReclaimCounters, "thresholdPagesPerSec", c.config.ReclaimThreshold)
+	}
+	if c.config.MaxCandidates > 0 {
+		klog.InfoS("Candidate cap per scan configured", "maxCandidates", c.config.MaxCandidates)
+	}
+	if len(c.config.NamespaceKillBudgets) > 0 {
+		klog.InfoS("Namespace kill budgets configured", "killsPerMinute", c.config.NamespaceKillBudgets)
+	}
+	if len(c.config.IgnoreContainers) > 0 {
+		klog.InfoS("Ignored containers configured", "containers", c.config.IgnoreContainers)
+	}
+	if c.config.AnnotateOwner {
+		klog.InfoS("Owner annotation enabled", "annotation", LastKillAnnotation)
+	}
 
 	// Startup check: scan cgroups to detect configuration issues early
 	c.checkCgroupsAtStartup()
 
-	ticker := time.NewTicker(c.config.PollInterval)
-	defer ticker.Stop()
+	// Start the staleness clock so a first reconcile that never returns is detected
+	c.lastReconcileTime.Store(time.Now().UnixNano())
+
+	// Reconciles run on a context that survives shutdown, so an in-flight kill
+	// batch can finish (including event emission) within ShutdownTimeout
+	reconcileCtx, cancelReconcile := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelReconcile()
+
+	// A timer rather than a ticker, so adaptive mode can change the interval
+	c.pollInterval = c.config.PollInterval
+	timer := time.NewTimer(c.pollInterval)
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
-		case <-ticker.C:
-			if err := c.reconcile(ctx); err != nil {
-				klog.ErrorS(err, "Reconcile failed")
+		case <-timer.C:
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				if err := c.reconcile(reconcileCtx); err != nil {
+					klog.ErrorS(err, "Reconcile failed")
+				}
+			}()
+
+			select {
+			case <-done:
+			case <-ctx.Done():
+				c.waitForInFlightReconcile(done, cancelReconcile)
+				return nil
 			}
+
+			timer.Reset(c.pollInterval)
 		}
 	}
 }
 
+// waitForInFlightReconcile waits up to ShutdownTimeout for the current
+// reconcile to finish, cancelling it if the timeout expires
+func (c *Controller) waitForInFlightReconcile(done <-chan struct{}, cancel context.CancelFunc) {
+	klog.InfoS("Shutdown requested, waiting for in-flight reconcile", "timeout", c.config.ShutdownTimeout)
+
+	timer := time.NewTimer(c.config.ShutdownTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		klog.InfoS("In-flight reconcile completed, shutdown clean")
+	case <-timer.C:
+		cancel()
+		klog.InfoS("Timed out waiting for in-flight reconcile, shutdown not clean", "timeout", c.config.ShutdownTimeout)
+	}
+}
+
 // checkCgroupsAtStartup scans cgroups once at startup to detect configuration issues early
 func (c *Controller) checkCgroupsAtStartup() {
 	result, err := c.config.CgroupScanner.FindPodCgroups()
@@ -94,27 +449,115 @@ func (c *Controller) checkCgroupsAtStartup() {
 	klog.InfoS("Startup cgroup check completed", "containerCgroups", len(result.Cgroups))
 
 	if len(result.Unrecognized) > 0 {
-		// Show up to 3 examples to avoid log spam
-		examples := result.Unrecognized
-		if len(examples) > 3 {
-			examples = examples[:3]
-		}
-		klog.InfoS("Found unrecognized cgroup patterns", "count", len(result.Unrecognized), "examples", examples)
+		klog.InfoS("Found unrecognized cgroup patterns", "count", len(result.Unrecognized), "examples", unrecognizedExamples(result.Unrecognized))
 	}
 }
 
+// unrecognizedExamples returns up to 3 unrecognized cgroup paths to avoid log spam
+func unrecognizedExamples(unrecognized []string) []string {
+	if len(unrecognized) > 3 {
+		return unrecognized[:3]
+	}
+	return unrecognized
+}
+
 func (c *Controller) reconcile(ctx context.Context) error {
-	return c.findAndKillOverThreshold(ctx)
+	start := time.Now()
+	// Counted up front, so a pass that never returns still shows the loop was alive
+	if c.config.Metrics != nil {
+		c.config.Metrics.ReconcilesTotal.Inc()
+	}
+	c.forgetDeletedPods()
+	c.updateNodeTaint(ctx, start)
+	c.updatePaused(ctx, start)
+
+	ioRate, ioOK := c.sampleSwapIO(start)
+	if ioOK && c.config.Metrics != nil {
+		c.config.Metrics.SwapInRate.Set(ioRate.in)
+		c.config.Metrics.SwapOutRate.Set(ioRate.out)
+	}
+	c.updateReclaim(start)
+	prevOverThreshold := c.lastOverThreshold
+
+	var err error
+	if c.config.ScanOnlyOnSwapIO && c.idleSinceLastPass(ioRate, ioOK) {
+		klog.V(4).InfoS("Skipped scan, no swap I/O and no pods over threshold")
+	} else {
+		err = c.findAndKillOverThreshold(ctx)
+	}
+
+	if c.config.AdaptivePoll {
+		c.adaptPollInterval(ioRate, ioOK, prevOverThreshold)
+	}
+
+	if err == nil {
+		c.lastReconcileTime.Store(time.Now().UnixNano())
+	}
+
+	if c.config.Metrics != nil {
+		c.updateInformerMetrics()
+		c.config.Metrics.ReconcileDuration.Observe(time.Since(start).Seconds())
+		if err != nil {
+			c.config.Metrics.ReconcileErrorsTotal.Inc()
+		} else {
+			c.config.Metrics.LastReconcileTimestamp.SetToCurrentTime()
+		}
+	}
+
+	return err
+}
+
+// updateInformerMetrics reports the pod cache size and last resync. A cache
+// far larger than a node can run means the spec.nodeName selector isn't scoping the watch.
+func (c *Controller) updateInformerMetrics() {
+	if c.config.PodInformer == nil {
+		return
+	}
+	c.config.Metrics.InformerPodsCached.Set(float64(len(c.config.PodInformer.ListPods())))
+	if resync := c.config.PodInformer.LastResync(); !resync.IsZero() {
+		c.config.Metrics.InformerLastResyncTime.Set(float64(resync.UnixNano()) / 1e9)
+	}
+}
+
+// CheckHealth returns an error if no reconcile has succeeded within
+// staleReconcileFactor poll intervals. Before Run starts it always succeeds.
+func (c *Controller) CheckHealth() error {
+	last := c.lastReconcileTime.Load()
+	if last == 0 {
+		return nil
+	}
+
+	age := time.Since(time.Unix(0, last))
+	maxAge := staleReconcileFactor * c.config.PollInterval
+	if age > maxAge {
+		return fmt.Errorf("last successful reconcile was %s ago (max %s)", age.Round(time.Millisecond), maxAge)
+	}
+	return nil
 }
 
 func (c *Controller) findAndKillOverThreshold(ctx context.Context) error {
 	// Phase 1: Scan cgroups for swap usage (NO API CALL)
-	candidates, err := c.scanCgroupsForSwap()
+	scanStart := time.Now()
+	candidates, err := c.scanWithTimeout(ctx)
+	if c.config.Metrics != nil {
+		c.config.Metrics.ScanDuration.Observe(time.Since(scanStart).Seconds())
+	}
 	if err != nil {
 		return err
 	}
 
+	c.setSwapNodeFractions(candidates)
+
+	// Compare against previous samples before the empty check so stale history is pruned
+	now := c.clock()
+	c.updateSwapHistory(candidates, now)
+
+	// Warn pods approaching the kill threshold (never deletes)
+	c.updateWarnings(candidates, now)
+
 	if len(candidates) == 0 {
+		c.setCandidateCounts(0, 0)
+		c.pruneEscalations(nil)
 		klog.V(3).InfoS("No pods using swap")
 		return nil
 	}
@@ -122,10 +565,14 @@ func (c *Controller) findAndKillOverThreshold(ctx context.Context) error {
 	// Filter to only pods over threshold
 	var overThreshold []PodCandidate
 	for _, cand := range candidates {
-		if cand.SwapPercent > c.config.SwapThresholdPercent {
+		if cand.SwapPercent > c.config.SwapThresholdPercent || c.otherTriggerFired(cand) {
 			overThreshold = append(overThreshold, cand)
+		} else {
+			c.recordDecision(cand, DecisionBelowThreshold, "")
 		}
 	}
+	c.setCandidateCounts(len(candidates), len(overThreshold))
+	c.pruneEscalations(overThreshold)
 
 	if len(overThreshold) == 0 {
 		// Log details of candidates at V(3) for debugging
@@ -145,23 +592,57 @@ func (c *Controller) findAndKillOverThreshold(ctx context.Context) error {
 		pod := c.config.PodInformer.GetPodByUID(cand.UID)
 		if pod == nil {
 			klog.V(3).InfoS("Pod not found in cache", "uid", cand.UID)
+			c.recordSkip(cand, skipReasonNotInCache)
 			continue
 		}
 
 		// Skip pods already terminating
 		if pod.DeletionTimestamp != nil {
 			klog.V(3).InfoS("Skipped pod, already terminating", "pod", klog.KRef(pod.Namespace, pod.Name))
+			c.recordSkip(cand, skipReasonTerminating)
 			continue
 		}
 
+		cand.Namespace = pod.Namespace
+		cand.Name = pod.Name
+
 		// Skip protected namespaces
 		if c.protectedNamespaces[pod.Namespace] {
 			klog.V(3).InfoS("Skipped pod, namespace protected", "pod", klog.KRef(pod.Namespace, pod.Name))
+			c.recordSkip(cand, skipReasonProtectedNamespace)
+			c.recordAudit(cand, audit.ActionSkippedProtected, nil)
+			continue
+		}
+
+		// Skip pods marked critical by label (labels come from the cached pod object)
+		if c.config.ProtectSelector != nil && c.config.ProtectSelector.Matches(labels.Set(pod.Labels)) {
+			klog.V(3).InfoS("Skipped pod, matches protect selector", "pod", klog.KRef(pod.Namespace, pod.Name), "selector", c.config.ProtectSelector.String())
+			c.recordSkip(cand, skipReasonProtectLabel)
+			c.recordAudit(cand, audit.ActionSkippedProtected, nil)
+			continue
+		}
+
+		// Skip pods whose owner would not (or should not) recreate them
+		if reason := c.ownerSkipReason(pod); reason != "" {
+			klog.V(3).InfoS("Skipped pod, excluded by owner", "pod", klog.KRef(pod.Namespace, pod.Name), "reason", reason)
+			c.recordSkip(cand, reason)
+			continue
+		}
+
+		// Pods already being disrupted or failing readiness are on their way out
+		if cond := c.excludedCondition(pod, now); cond != "" {
+			klog.V(3).InfoS("Skipped pod, excluded by condition", "pod", klog.KRef(pod.Namespace, pod.Name), "condition", cond)
+			c.recordSkip(cand, skipReasonCondition)
+			continue
+		}
+
+		// Give the pod (or an autoscaler) a chance to recover before killing it
+		if !c.escalationDue(cand, now) {
+			klog.V(3).InfoS("Skipped pod, escalation delay pending", "pod", klog.KRef(pod.Namespace, pod.Name), "overSince", c.overSince[cand.UID])
+			c.recordSkip(cand, skipReasonEscalation)
 			continue
 		}
 
-		cand.Namespace = pod.Namespace
-		cand.Name = pod.Name
 		resolved = append(resolved, cand)
 	}
 
@@ -173,20 +654,79 @@ func (c *Controller) findAndKillOverThreshold(ctx context.Context) error {
 	// Log all resolved candidates
 	klog.V(2).InfoS("Found pods over threshold", "count", len(resolved))
 	for _, cand := range resolved {
-		klog.V(2).InfoS("Pod over threshold", "pod", klog.KRef(cand.Namespace, cand.Name), "swapPercent", cand.SwapPercent)
+		klog.V(2).InfoS("Pod over threshold", "pod", klog.KRef(cand.Namespace, cand.Name), "swapPercent", cand.SwapPercent, "psiFullAvg10", cand.PSIFullAvg10, "swapGrowthBytesPerSec", cand.SwapGrowthRate, "swapLimitEvents", cand.SwapLimitEventsDelta, "memoryHighEvents", cand.MemoryHighEventsDelta, "pidsPercent", cand.PidsPercent, "swapNodeFraction", cand.SwapNodeFraction, "swapOfLimitPercent", cand.SwapOfLimitPercent)
 	}
 
-	// Kill pods over threshold (sorted by swap percent descending)
-	sort.Slice(resolved, func(i, j int) bool {
-		return resolved[i].SwapPercent > resolved[j].SwapPercent
+	// Kill pods over threshold (sorted by composite score, or footprint,
+	// descending; ties broken by ascending UID so the order is reproducible across runs)
+	weights := c.scoreWeights()
+	for i := range resolved {
+		if c.config.ScoreExpr != nil {
+			resolved[i].Score = c.config.ScoreExpr.score(resolved[i])
+		} else {
+			resolved[i].Score = weights.score(resolved[i])
+		}
+	}
+	sortKey := func(cand PodCandidate) float64 { return cand.Score }
+	if c.config.SortBy == SortByFootprint {
+		sortKey = func(cand PodCandidate) float64 { return float64(cand.footprint()) }
+	}
+	sort.SliceStable(resolved, func(i, j int) bool {
+		if ki, kj := sortKey(resolved[i]), sortKey(resolved[j]); ki != kj {
+			return ki > kj
+		}
+		return resolved[i].UID < resolved[j].UID
 	})
 
-	var killed int
-	for _, cand := range resolved {
-		if err := c.terminatePod(ctx, cand); err != nil {
+	// The kernel is already freeing memory and may relieve the pressure on its own
+	if c.deferForReclaim() {
+		klog.InfoS("Deferring kills for one pass, node memory reclaim is active", "pods", len(resolved))
+		for _, cand := range resolved {
+			c.recordSkip(cand, skipReasonActiveReclaim)
+		}
+		return nil
+	}
+
+	// Under acute node pressure, long grace periods would keep victims swapping for minutes
+	emergencyGrace := c.emergencyGracePeriod()
+
+	// attempted also counts kills withheld by dry-run, grace or pause, so
+	// KillTopN shows what a live pass would do
+	var killed, attempted int
+	for i, cand := range resolved {
+		// Freeing the worst offenders often relieves enough pressure; the rest wait for the next pass
+		if c.config.KillTopN > 0 && attempted >= c.config.KillTopN {
+			klog.V(2).InfoS("Kill limit reached, sparing remaining pods until next pass", "limit", c.config.KillTopN, "remaining", len(resolved)-i)
+			for _, spared := range resolved[i:] {
+				c.recordSkip(spared, skipReasonKillLimit)
+			}
+			break
+		}
+		// Deletes go by namespace/name, so make sure the name still refers to the scanned pod
+		uid, ok := c.currentPodUID(cand)
+		if !ok {
+			klog.InfoS("Skipped pod, no longer the pod that was scanned", "pod", klog.KRef(cand.Namespace, cand.Name), "uid", cand.UID)
+			c.recordSkip(cand, skipReasonPodReplaced)
+			continue
+		}
+		// A noisy namespace must not use up kills other namespaces need; the pod waits for the window to move
+		if c.namespaceBudgetExhausted(cand.Namespace, now) {
+			c.recordSkip(cand, skipReasonNamespaceBudget)
+			continue
+		}
+		cand.UID = uid
+		cand.GracePeriodSeconds = c.gracePeriodOverride(cand.UID, emergencyGrace)
+		ok, err := c.terminatePod(ctx, cand)
+		if err != nil {
 			klog.ErrorS(err, "Failed to delete pod", "pod", klog.KRef(cand.Namespace, cand.Name))
 			continue
 		}
+		attempted++
+		// Withheld kills (dry-run, grace, pause) don't use the namespace budget
+		if !ok {
+			continue
+		}
+		c.recordNamespaceKill(cand.Namespace, now)
 		killed++
 	}
 
@@ -197,78 +737,346 @@ func (c *Controller) findAndKillOverThreshold(ctx context.Context) error {
 	return nil
 }
 
-// scanCgroupsForSwap scans cgroups for pods using swap without calling the API.
-// It filters by QoS class (burstable only) and returns candidates with swap usage.
-func (c *Controller) scanCgroupsForSwap() ([]PodCandidate, error) {
-	// Find all container cgroups via filesystem walk
-	cgroupsResult, err := c.config.CgroupScanner.FindPodCgroups()
-	if err != nil {
-		klog.ErrorS(err, "Failed to find pod cgroups")
-		return nil, nil
-	}
+// updateSwapHistory sets SwapGrowthRate, SwapLimitEventsDelta and MemoryHighEventsDelta on each
+// candidate from the previous sample and replaces the history with the current
+// samples. Pods absent from candidates (stopped using swap or gone) are dropped.
+func (c *Controller) updateSwapHistory(candidates []PodCandidate, now time.Time) {
+	history := make(map[string]swapSample, len(candidates))
 
-	// Track processed pods by UID to avoid duplicates (multiple containers per pod)
-	processedPods := make(map[string]*PodCandidate)
+	for i := range candidates {
+		cand := &candidates[i]
+		sample := swapSample{bytes: cand.SwapBytes, limitEvents: cand.SwapLimitEvents, highEvents: cand.MemoryHighEvents, at: now}
 
-	for _, cgroupPath := range cgroupsResult.Cgroups {
-		// Filter by QoS: only Burstable pods get swap in LimitedSwap mode
-		qos := cgroup.ExtractQoS(cgroupPath)
-		if qos != "burstable" {
-			klog.V(4).InfoS("Skipped cgroup, QoS not burstable", "cgroupPath", cgroupPath, "qos", qos)
-			continue
+		if prev, ok := c.swapHistory[cand.UID]; ok {
+			if elapsed := now.Sub(prev.at).Seconds(); elapsed > 0 {
+				// Delta in int64 first so large byte counts don't lose precision in float64
+				cand.SwapGrowthRate = float64(cand.SwapBytes-prev.bytes) / elapsed
+			}
+			// Counters restart from zero when a container restarts
+			if cand.SwapLimitEvents > prev.limitEvents {
+				cand.SwapLimitEventsDelta = cand.SwapLimitEvents - prev.limitEvents
+			}
+			if cand.MemoryHighEvents > prev.highEvents {
+				cand.MemoryHighEventsDelta = cand.MemoryHighEvents - prev.highEvents
+			}
+			sample.namespace = prev.namespace
+			sample.name = prev.name
 		}
 
-		// Extract pod UID from cgroup path
-		uid := cgroup.ExtractPodUID(cgroupPath)
-		if uid == "" {
-			klog.Warning("Could not extract pod UID from cgroup", "cgroupPath", cgroupPath)
-			continue
+		if c.config.Metrics != nil && c.config.PodInformer != nil {
+			if sample.name == "" {
+				if pod := c.config.PodInformer.GetPodByUID(cand.UID); pod != nil {
+					sample.namespace = pod.Namespace
+					sample.name = pod.Name
+				}
+			}
+			if sample.name != "" {
+				c.config.Metrics.PodSwapGrowthRate.WithLabelValues(sample.namespace, sample.name).Set(cand.SwapGrowthRate)
+				c.config.Metrics.PodSwapPercent.WithLabelValues(sample.namespace, sample.name).Set(cand.SwapPercent)
+				c.config.Metrics.PodSwapNodeFrac.WithLabelValues(sample.namespace, sample.name).Set(cand.SwapNodeFraction)
+				// Pods without a swap limit have no allocation to be a percentage of
+				if cand.SwapOfLimitPercent > 0 {
+					c.config.Metrics.PodSwapOfLimit.WithLabelValues(sample.namespace, sample.name).Set(cand.SwapOfLimitPercent)
+				} else {
+					c.config.Metrics.PodSwapOfLimit.DeleteLabelValues(sample.namespace, sample.name)
+				}
+			}
 		}
 
-		containerMetrics, err := c.config.CgroupScanner.GetContainerMetrics(cgroupPath)
-		if err != nil {
-			klog.Warning("Failed to get metrics for cgroup", "cgroupPath", cgroupPath, "err", err)
-			continue
+		history[cand.UID] = sample
+	}
+
+	// Drop metric series for pods that are no longer tracked. Series are
+	// deleted individually rather than reset, so pods still present keep
+	// their values between scrapes.
+	if c.config.Metrics != nil {
+		for uid, prev := range c.swapHistory {
+			if _, ok := history[uid]; !ok && prev.name != "" {
+				c.config.Metrics.PodSwapGrowthRate.DeleteLabelValues(prev.namespace, prev.name)
+				c.config.Metrics.PodSwapPercent.DeleteLabelValues(prev.namespace, prev.name)
+				c.config.Metrics.PodSwapNodeFrac.DeleteLabelValues(prev.namespace, prev.name)
+				c.config.Metrics.PodSwapOfLimit.DeleteLabelValues(prev.namespace, prev.name)
+			}
 		}
+	}
 
-		// Skip if not using swap
-		if containerMetrics.SwapCurrent == 0 {
-			continue
+	c.swapHistory = history
+}
+
+// otherTriggerFired reports whether any kill trigger besides the swap percent
+// threshold fires for the candidate
+func (c *Controller) otherTriggerFired(cand PodCandidate) bool {
+	return c.growthExceeded(cand) || c.swapLimitHit(cand) || c.memoryHighHit(cand) || c.pidsExceeded(cand) || c.nodeFractionExceeded(cand)
+}
+
+// growthExceeded reports whether the candidate's swap growth rate triggers a kill
+func (c *Controller) growthExceeded(cand PodCandidate) bool {
+	threshold := c.config.SwapGrowthThreshold
+	return threshold > 0 && cand.SwapGrowthRate > threshold
+}
+
+// swapLimitHit reports whether the candidate was denied swap since the previous reconcile
+func (c *Controller) swapLimitHit(cand PodCandidate) bool {
+	return c.config.KillOnSwapLimitEvents && cand.SwapLimitEventsDelta > 0
+}
+
+// setSwapNodeFractions sets SwapNodeFraction on each candidate from the
+// node's SwapTotal. If meminfo can't be read the fractions stay 0, so the
+// node-fraction trigger never fires on missing data.
+func (c *Controller) setSwapNodeFractions(candidates []PodCandidate) {
+	if len(candidates) == 0 {
+		return
+	}
+
+	total, err := c.config.CgroupScanner.GetSwapTotal()
+	if err != nil {
+		klog.V(2).InfoS("Failed to read node swap total", "err", err)
+		return
+	}
+	for i := range candidates {
+		candidates[i].SwapNodeFraction = percentOf(candidates[i].SwapBytes, total) / 100
+	}
+}
+
+// nodeFractionExceeded reports whether the candidate holds too much of the node's swap
+func (c *Controller) nodeFractionExceeded(cand PodCandidate) bool {
+	threshold := c.config.NodeFractionThreshold
+	return threshold > 0 && cand.SwapNodeFraction > threshold
+}
+
+// memoryHighHit reports whether the candidate was throttled at memory.high
+// since the previous reconcile, a sign of aggressive reclaim before swap fills
+func (c *Controller) memoryHighHit(cand PodCandidate) bool {
+	return c.config.KillOnMemoryHigh && cand.MemoryHighEventsDelta > 0
+}
+
+// pidsExceeded reports whether the candidate is close to its PID limit
+// (swap thrashing caused by a fork bomb)
+func (c *Controller) pidsExceeded(cand PodCandidate) bool {
+	threshold := c.config.PidsThresholdPercent
+	return threshold > 0 && cand.PidsPercent > threshold
+}
+
+// CandidateStatus describes a pod using swap as seen by a read-only scan.
+// OverSwapThreshold compares swap percent alone to SwapThresholdPercent;
+// the other kill triggers depend on per-pod history only the reconcile loop
+// keeps, so a pod they would kill can still read false.
+type CandidateStatus struct {
+	UID               string  `json:"uid"`
+	Namespace         string  `json:"namespace,omitempty"`
+	Name              string  `json:"name,omitempty"`
+	SwapBytes         int64   `json:"swapBytes"`
+	SwapPercent       float64 `json:"swapPercent"`
+	OverSwapThreshold bool    `json:"overSwapThreshold"`
+	Protected         bool    `json:"protected"`
+}
+
+// ListCandidates scans cgroups and resolves pod names without killing anything.
+// It only reads the filesystem and the informer cache, and records no metrics
+// or controller state, so it is safe to call concurrently with the reconcile loop.
+// The scan is bounded by ScanTimeout like the reconcile scan, and a call made
+// while a previous one is still blocked fails instead of starting another.
+func (c *Controller) ListCandidates(ctx context.Context) ([]CandidateStatus, error) {
+	result, err := c.boundedScan(ctx, &c.listInFlight)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]CandidateStatus, 0, len(result.Candidates))
+	for _, cand := range result.Candidates {
+		status := CandidateStatus{
+			UID:               cand.UID,
+			SwapBytes:         cand.SwapBytes,
+			SwapPercent:       cand.SwapPercent,
+			OverSwapThreshold: cand.SwapPercent > c.config.SwapThresholdPercent,
+		}
+		if pod := c.config.PodInformer.GetPodByUID(cand.UID); pod != nil {
+			status.Namespace = pod.Namespace
+			status.Name = pod.Name
+			status.Protected = c.protectedNamespaces[pod.Namespace]
 		}
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].SwapPercent > statuses[j].SwapPercent
+	})
+
+	return statuses, nil
+}
 
-		// Calculate swap percentage for THIS container
-		var swapPercent float64
-		if containerMetrics.MemoryMax > 0 {
-			swapPercent = float64(containerMetrics.SwapCurrent) / float64(containerMetrics.MemoryMax) * 100
+// errScanInFlight and errScanTimeout are returned by boundedScan
+var (
+	errScanInFlight = errors.New("previous cgroup scan still in flight")
+	errScanTimeout  = errors.New("cgroup scan exceeded scan timeout")
+)
+
+// scanWithTimeout runs scanCgroupsForSwap bounded by ScanTimeout (see
+// boundedScan). The scan's results are recorded here, on the reconcile
+// loop, and only if it finished in time.
+func (c *Controller) scanWithTimeout(ctx context.Context) ([]PodCandidate, error) {
+	result, err := c.boundedScan(ctx, &c.scanInFlight)
+	switch {
+	case errors.Is(err, errScanInFlight):
+		klog.InfoS("Previous cgroup scan still blocked, skipping reconcile", "scanTimeout", c.config.ScanTimeout)
+	case errors.Is(err, errScanTimeout):
+		klog.Warningf("Cgroup scan exceeded scan timeout %s, skipping reconcile", c.config.ScanTimeout)
+		if c.config.Metrics != nil {
+			c.config.Metrics.ScanTimeoutsTotal.Inc()
 		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.recordScan(result)
+	return result.Candidates, nil
+}
 
-		if existing, ok := processedPods[uid]; ok {
-			// Pod already seen - take max swap percentage
-			// If ANY container exceeds threshold, the pod should be killed
-			if swapPercent > existing.SwapPercent {
-				existing.SwapPercent = swapPercent
-			}
-		} else {
-			processedPods[uid] = &PodCandidate{
-				UID:         uid,
-				SwapPercent: swapPercent,
-			}
+// boundedScan runs scanSwap bounded by ScanTimeout. A read blocked in the
+// kernel (e.g. a hung filesystem) never observes ctx, so the scan runs in
+// its own goroutine and is abandoned on timeout; it exits at its next ctx
+// check once the read returns. inFlight stays set until then, and further
+// calls sharing it fail with errScanInFlight rather than piling up more
+// goroutines on the same hung read.
+func (c *Controller) boundedScan(ctx context.Context, inFlight *atomic.Bool) (*SwapScan, error) {
+	if c.config.ScanTimeout <= 0 {
+		return c.scanSwap(ctx)
+	}
+
+	if !inFlight.CompareAndSwap(false, true) {
+		return nil, errScanInFlight
+	}
+
+	scanCtx, cancel := context.WithTimeout(ctx, c.config.ScanTimeout)
+	defer cancel()
+
+	type scanResult struct {
+		scan *SwapScan
+		err  error
+	}
+	done := make(chan scanResult, 1)
+	go func() {
+		defer inFlight.Store(false)
+		scan, err := c.scanSwap(scanCtx)
+		done <- scanResult{scan, err}
+	}()
+
+	var result scanResult
+	select {
+	case result = <-done:
+	case <-scanCtx.Done():
+		result.err = scanCtx.Err()
+	}
+
+	// Only our own deadline counts; a cancelled parent is shutdown, not a timeout
+	if errors.Is(result.err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return nil, fmt.Errorf("%w %s", errScanTimeout, c.config.ScanTimeout)
+	}
+	return result.scan, result.err
+}
+
+// scanCgroupsForSwap scans cgroups for pods using swap and records the scan
+// metrics and state. Only called from the reconcile loop; see scanSwap for
+// the side-effect-free scan.
+func (c *Controller) scanCgroupsForSwap(ctx context.Context) ([]PodCandidate, error) {
+	result, err := c.scanSwap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.recordScan(result)
+	return result.Candidates, nil
+}
+
+// scanSwap scans cgroups for pods using swap without calling the API
+// (IgnoreContainers additionally reads the informer cache). It has no side
+// effects beyond logging, so it is safe from any goroutine. See ScanSwapUsage.
+func (c *Controller) scanSwap(ctx context.Context) (*SwapScan, error) {
+	return ScanSwapUsage(ctx, c.config.CgroupScanner, func(uid, cgroupPath string) bool {
+		if name := c.ignoredContainerName(uid, cgroupPath); name != "" {
+			klog.V(4).InfoS("Skipped cgroup, container ignored", "cgroupPath", cgroupPath, "container", name)
+			return true
+		}
+		return false
+	}, c.config.MaxCandidates)
+}
+
+// recordScan applies a reconcile scan's results: the max-candidates warning
+// and the scan metrics. Only touched from the reconcile loop.
+func (c *Controller) recordScan(result *SwapScan) {
+	if result.Dropped > 0 && !c.candidatesCapped {
+		klog.InfoS("Swapping pods exceed max candidates, keeping the highest-swap ones", "maxCandidates", c.config.MaxCandidates, "dropped", result.Dropped)
+	} else if result.Dropped > 0 {
+		klog.V(2).InfoS("Swapping pods exceed max candidates", "maxCandidates", c.config.MaxCandidates, "dropped", result.Dropped)
+	}
+	c.candidatesCapped = result.Dropped > 0
+
+	if c.config.Metrics != nil {
+		// Scopes matching no runtime prefix are invisible to the controller; surface them every scan
+		c.config.Metrics.UnrecognizedCgroups.Set(float64(result.Unrecognized))
+		// Non-burstable pods swapping on a LimitedSwap node indicate a misconfiguration
+		for qos, count := range result.SwappingByQoS {
+			c.config.Metrics.CandidatesByQoS.WithLabelValues(qos).Set(float64(count))
+		}
+		// The same file failing across many pods points at the kernel or permissions, not the pods
+		for file, count := range result.ReadErrors {
+			c.config.Metrics.ScanReadErrors.WithLabelValues(file).Add(float64(count))
 		}
 	}
+}
 
-	// Convert map to slice
-	var candidates []PodCandidate
-	for _, cand := range processedPods {
-		candidates = append(candidates, *cand)
+// ignoredContainerName returns the container's name if it is listed in
+// IgnoreContainers, or "". Names aren't encoded in cgroup paths, so this is
+// the one place the scan consults the informer cache; a container whose pod
+// or status isn't cached yet is not ignored.
+func (c *Controller) ignoredContainerName(uid, cgroupPath string) string {
+	if len(c.ignoreContainers) == 0 || c.config.PodInformer == nil {
+		return ""
 	}
 
-	return candidates, nil
+	pod := c.config.PodInformer.GetPodByUID(uid)
+	if pod == nil {
+		return ""
+	}
+	name := metrics.FindContainerName(pod, c.config.CgroupScanner.ExtractContainerID(cgroupPath))
+	if !c.ignoreContainers[name] {
+		return ""
+	}
+	return name
 }
 
-func (c *Controller) terminatePod(ctx context.Context, cand PodCandidate) error {
-	if c.config.DryRun {
-		klog.InfoS("Would delete pod (dry-run)", "pod", klog.KRef(cand.Namespace, cand.Name), "swapPercent", cand.SwapPercent)
-		return nil
+// terminatePod kills cand, or only reports it while kills are withheld by
+// dry-run, the startup grace period or the node pause annotation. killed is
+// true only if the kill action actually ran and succeeded.
+func (c *Controller) terminatePod(ctx context.Context, cand PodCandidate) (killed bool, err error) {
+	// Dry-run namespaces are the inverse of protected ones: evaluated and reported, never enforced
+	if c.config.DryRun || c.dryRunNamespaces[cand.Namespace] {
+		klog.InfoS("Would delete pod (dry-run)", "pod", klog.KRef(cand.Namespace, cand.Name), "swapPercent", cand.SwapPercent, "namespaceDryRun", !c.config.DryRun)
+		c.countWouldKill(cand)
+		c.recordAudit(cand, audit.ActionDryRun, nil)
+		if c.config.DryRun {
+			c.recordDecision(cand, DecisionDryRun, dryRunReasonGlobal)
+		} else {
+			c.recordDecision(cand, DecisionDryRun, dryRunReasonNamespace)
+		}
+		return false, nil
+	}
+
+	// Right after a rollout restarts every controller at once, let pods settle before killing
+	if c.inStartupGrace(time.Now()) {
+		klog.InfoS("Would delete pod (startup grace period)", "pod", klog.KRef(cand.Namespace, cand.Name), "swapPercent", cand.SwapPercent, "graceRemaining", c.config.StartupGracePeriod-time.Since(c.startedAt))
+		c.countWouldKill(cand)
+		c.recordAudit(cand, audit.ActionDryRun, nil)
+		c.recordDecision(cand, DecisionDryRun, dryRunReasonStartup)
+		return false, nil
+	}
+
+	// Operators paused kills declaratively on the node, e.g. for maintenance
+	if c.paused {
+		klog.InfoS("Would delete pod (paused by node annotation)", "pod", klog.KRef(cand.Namespace, cand.Name), "swapPercent", cand.SwapPercent, "annotation", PauseAnnotation)
+		c.countWouldKill(cand)
+		c.recordAudit(cand, audit.ActionDryRun, nil)
+		c.recordDecision(cand, DecisionDryRun, dryRunReasonPaused)
+		return false, nil
 	}
 
 	// Emit Kubernetes event before deleting (if event recorder is configured)
@@ -276,7 +1084,7 @@ func (c *Controller) terminatePod(ctx context.Context, cand PodCandidate) error
 		// Get the pod object from informer cache to attach the event to
 		pod := c.config.PodInformer.GetPodByUID(cand.UID)
 		if pod != nil {
-			c.config.EventRecorder.Eventf(pod, corev1.EventTypeWarning, "Soomkilled",
+			c.config.EventRecorder.Eventf(pod, corev1.EventTypeWarning, c.eventReason(),
 				"Pod %s deleted by kube-soomkiller on node %s: swap usage %.1f%%",
 				cand.Name, c.config.NodeName, cand.SwapPercent)
 		} else {
@@ -284,11 +1092,182 @@ func (c *Controller) terminatePod(ctx context.Context, cand PodCandidate) error
 		}
 	}
 
-	err := c.config.K8sClient.CoreV1().Pods(cand.Namespace).Delete(ctx, cand.Name, metav1.DeleteOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to delete pod %s/%s: %w", cand.Namespace, cand.Name, err)
+	c.annotateOwner(ctx, cand, time.Now())
+
+	// Tracked before the call, as a fast delete can leave the cache before it returns
+	c.trackKill(cand.UID, time.Now())
+	if err := c.killAction().Execute(ctx, cand); err != nil {
+		c.untrackKill(cand.UID)
+		c.recordAudit(cand, audit.ActionDeleteFailed, err)
+		c.recordDecision(cand, DecisionKillFailed, err.Error())
+		return false, err
 	}
 
-	klog.InfoS("Deleted pod", "pod", klog.KRef(cand.Namespace, cand.Name), "swapPercent", cand.SwapPercent, "reason", "swap threshold exceeded")
-	return nil
+	klog.InfoS("Deleted pod", "pod", klog.KRef(cand.Namespace, cand.Name), "swapPercent", cand.SwapPercent, "swapOfLimitPercent", cand.SwapOfLimitPercent, "reason", "swap threshold exceeded")
+	if c.config.Metrics != nil {
+		c.config.Metrics.PodsKilledTotal.Inc()
+		c.config.Metrics.LastKillTimestamp.SetToCurrentTime()
+		// Replace rather than add, so the gauge never holds more than one pod
+		if c.lastKilled.Name != "" {
+			c.config.Metrics.LastKillInfo.DeleteLabelValues(c.lastKilled.Namespace, c.lastKilled.Name)
+		}
+		c.config.Metrics.LastKillInfo.WithLabelValues(cand.Namespace, cand.Name).SetToCurrentTime()
+		c.lastKilled = types.NamespacedName{Namespace: cand.Namespace, Name: cand.Name}
+	}
+	c.recordAudit(cand, audit.ActionDeleted, nil)
+	c.recordDecision(cand, DecisionKilled, "")
+	return true, nil
+}
+
+// countWouldKill counts a kill withheld by dry-run or the startup grace
+// period in PodsWouldKill, once per pod, so it projects PodsKilledTotal
+func (c *Controller) countWouldKill(cand PodCandidate) {
+	if c.config.Metrics == nil || c.wouldKill[cand.UID] {
+		return
+	}
+	c.wouldKill[cand.UID] = true
+	c.config.Metrics.PodsWouldKill.Inc()
+}
+
+// currentPodUID returns the API UID of the pod the informer currently has
+// under cand's namespace/name, and false if that is not the pod whose cgroup
+// was scanned (deleted, or replaced by a new pod with the same name)
+func (c *Controller) currentPodUID(cand PodCandidate) (string, bool) {
+	byName := c.config.PodInformer.GetPod(cand.Namespace, cand.Name)
+	byUID := c.config.PodInformer.GetPodByUID(cand.UID)
+	if byName == nil || byUID == nil || byName.UID != byUID.UID {
+		return "", false
+	}
+	return string(byName.UID), true
+}
+
+// inStartupGrace reports whether now falls within StartupGracePeriod of Run starting
+func (c *Controller) inStartupGrace(now time.Time) bool {
+	return !c.startedAt.IsZero() && now.Sub(c.startedAt) < c.config.StartupGracePeriod
+}
+
+// percentOf returns part as a percentage of whole. Byte values stay int64
+// and are only converted for the division. A zero or negative whole yields 0
+// rather than NaN or Inf, which would break Prometheus gauges and sort ordering.
+func percentOf(part, whole int64) float64 {
+	if whole <= 0 {
+		return 0
+	}
+	return finite(float64(part) / float64(whole) * 100)
+}
+
+// finite returns v, or 0 if v is NaN or Inf
+func finite(v float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0
+	}
+	return v
+}
+
+// addBytes sums two non-negative byte counts, saturating at math.MaxInt64
+// instead of wrapping negative
+func addBytes(a, b int64) int64 {
+	if a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	return a + b
+}
+
+// ownerSkipReason returns the skip reason for a pod excluded by its owner
+// references, or "" if the pod may be killed. Owner references come from the
+// cached pod object, so no API call is made.
+func (c *Controller) ownerSkipReason(pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil && len(pod.OwnerReferences) > 0 {
+		// No controller flag set; fall back to the first owner
+		owner = &pod.OwnerReferences[0]
+	}
+
+	// A killed DaemonSet pod comes straight back on this node and resumes swapping
+	if c.config.ProtectDaemonSetPods && owner != nil && owner.Kind == "DaemonSet" {
+		return skipReasonDaemonSet
+	}
+	if c.config.SkipUnmanaged && (owner == nil || owner.Kind == "Pod") {
+		return skipReasonUnmanaged
+	}
+	if owner != nil && c.skipOwnerKinds[owner.Kind] {
+		return skipReasonOwnerKind
+	}
+
+	return ""
+}
+
+// excludedCondition returns the condition that excludes the pod from kills,
+// or "" if none does: a True condition listed in ExcludeConditions, or Ready
+// False for at least ExcludeNotReadyAfter. Conditions come from the cached
+// pod object.
+func (c *Controller) excludedCondition(pod *corev1.Pod, now time.Time) corev1.PodConditionType {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Status == corev1.ConditionTrue && c.excludeConditions[cond.Type] {
+			return cond.Type
+		}
+		if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionFalse && c.config.ExcludeNotReadyAfter > 0 &&
+			now.Sub(cond.LastTransitionTime.Time) >= c.config.ExcludeNotReadyAfter {
+			return cond.Type
+		}
+	}
+	return ""
+}
+
+// setCandidateCounts records the pods-using-swap and over-threshold counts
+func (c *Controller) setCandidateCounts(usingSwap, overThreshold int) {
+	c.lastOverThreshold = overThreshold
+	if c.config.Metrics == nil {
+		return
+	}
+	c.config.Metrics.CandidatePodsCount.Set(float64(usingSwap))
+	c.config.Metrics.PodsOverThreshold.Set(float64(overThreshold))
+}
+
+// killAction returns the configured kill action, falling back to deletion
+func (c *Controller) killAction() KillAction {
+	if c.config.KillAction == nil {
+		return &DeleteAction{Client: c.config.K8sClient}
+	}
+	return c.config.KillAction
+}
+
+// eventReason returns the configured kill event reason
+func (c *Controller) eventReason() string {
+	if c.config.EventReason == "" {
+		return DefaultEventReason
+	}
+	return c.config.EventReason
+}
+
+// recordSkip counts an over-threshold pod that was spared
+func (c *Controller) recordSkip(cand PodCandidate, reason string) {
+	c.recordDecision(cand, DecisionSkipped, reason)
+	if c.config.Metrics != nil {
+		c.config.Metrics.PodsSkippedTotal.WithLabelValues(reason).Inc()
+	}
+}
+
+// recordAudit appends a kill decision to the audit log, if configured
+func (c *Controller) recordAudit(cand PodCandidate, action string, actionErr error) {
+	if c.config.AuditLog == nil {
+		return
+	}
+
+	entry := audit.Entry{
+		Node:        c.config.NodeName,
+		Namespace:   cand.Namespace,
+		Name:        cand.Name,
+		UID:         cand.UID,
+		SwapBytes:   cand.SwapBytes,
+		SwapPercent: cand.SwapPercent,
+		Action:      action,
+	}
+	if actionErr != nil {
+		entry.Error = actionErr.Error()
+	}
+
+	if err := c.config.AuditLog.Record(entry); err != nil {
+		klog.ErrorS(err, "Failed to write audit log", "pod", klog.KRef(cand.Namespace, cand.Name))
+	}
 }
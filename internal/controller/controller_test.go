@@ -2,16 +2,33 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rophy/kube-soomkiller/internal/audit"
 	"github.com/rophy/kube-soomkiller/internal/cgroup"
+	"github.com/rophy/kube-soomkiller/internal/metrics"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 )
 
 // Helper to create a fake cgroup with metrics
@@ -67,7 +84,7 @@ func TestTerminatePod_DryRun(t *testing.T) {
 		},
 	}
 
-	err := c.terminatePod(context.Background(), PodCandidate{
+	_, err := c.terminatePod(context.Background(), PodCandidate{
 		Namespace: "default",
 		Name:      "test-pod",
 	})
@@ -83,6 +100,103 @@ func TestTerminatePod_DryRun(t *testing.T) {
 	}
 }
 
+func TestTerminatePod_WouldKillCounter(t *testing.T) {
+	m := metrics.NewMetrics("test-node")
+	informer := newTestPodInformer(t)
+	c := New(Config{
+		DryRun:      true,
+		PodInformer: informer,
+		Metrics:     m,
+	})
+
+	first := PodCandidate{UID: "uid-first", Namespace: "default", Name: "first"}
+	second := PodCandidate{UID: "uid-second", Namespace: "default", Name: "second"}
+
+	// A pod staying over threshold across passes counts once, as its kill would
+	for _, cand := range []PodCandidate{first, first, second} {
+		if _, err := c.terminatePod(context.Background(), cand); err != nil {
+			t.Fatalf("terminatePod(%s) error = %v", cand.Name, err)
+		}
+	}
+	if got := testutil.ToFloat64(m.PodsWouldKill); got != 2 {
+		t.Errorf("PodsWouldKill = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.PodsKilledTotal); got != 0 {
+		t.Errorf("PodsKilledTotal = %v, want 0 in dry-run", got)
+	}
+
+	// Deleted pods are forgotten, so the set stays bounded
+	informer.podDeleted("uid-first")
+	c.forgetDeletedPods()
+	if _, err := c.terminatePod(context.Background(), first); err != nil {
+		t.Fatalf("terminatePod(first) error = %v", err)
+	}
+	if got := testutil.ToFloat64(m.PodsWouldKill); got != 3 {
+		t.Errorf("PodsWouldKill after the pod was deleted and seen again = %v, want 3", got)
+	}
+}
+
+func TestTerminatePod_DryRunNamespaces(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		createPodWithUID("observed-pod", "staging", "test-node", "pod-uid-123", corev1.PodQOSBurstable),
+		createPodWithUID("enforced-pod", "batch", "test-node", "pod-uid-456", corev1.PodQOSBurstable),
+	)
+
+	c := New(Config{
+		DryRun:           false,
+		DryRunNamespaces: []string{"staging"},
+		K8sClient:        fakeClient,
+		PodInformer:      newTestPodInformer(t),
+	})
+
+	for _, cand := range []PodCandidate{
+		{Namespace: "staging", Name: "observed-pod"},
+		{Namespace: "batch", Name: "enforced-pod"},
+	} {
+		if _, err := c.terminatePod(context.Background(), cand); err != nil {
+			t.Fatalf("terminatePod(%s) unexpected error: %v", cand.Name, err)
+		}
+	}
+
+	if _, err := fakeClient.CoreV1().Pods("staging").Get(context.Background(), "observed-pod", metav1.GetOptions{}); err != nil {
+		t.Error("pod in dry-run namespace was deleted")
+	}
+	if _, err := fakeClient.CoreV1().Pods("batch").Get(context.Background(), "enforced-pod", metav1.GetOptions{}); err == nil {
+		t.Error("pod outside dry-run namespaces was not deleted")
+	}
+}
+
+func TestTerminatePod_StartupGracePeriod(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		createPodWithUID("test-pod", "default", "test-node", "pod-uid-123", corev1.PodQOSBurstable),
+	)
+
+	c := New(Config{
+		StartupGracePeriod: time.Minute,
+		K8sClient:          fakeClient,
+		PodInformer:        newTestPodInformer(t),
+	})
+	cand := PodCandidate{Namespace: "default", Name: "test-pod"}
+
+	// Within the grace period the pod is only reported
+	c.startedAt = time.Now()
+	if _, err := c.terminatePod(context.Background(), cand); err != nil {
+		t.Fatalf("terminatePod() unexpected error: %v", err)
+	}
+	if _, err := fakeClient.CoreV1().Pods("default").Get(context.Background(), "test-pod", metav1.GetOptions{}); err != nil {
+		t.Fatal("pod deleted during startup grace period")
+	}
+
+	// Once it has elapsed the pod is deleted
+	c.startedAt = time.Now().Add(-2 * time.Minute)
+	if _, err := c.terminatePod(context.Background(), cand); err != nil {
+		t.Fatalf("terminatePod() unexpected error: %v", err)
+	}
+	if _, err := fakeClient.CoreV1().Pods("default").Get(context.Background(), "test-pod", metav1.GetOptions{}); err == nil {
+		t.Error("pod not deleted after startup grace period")
+	}
+}
+
 func TestTerminatePod_ActualDelete(t *testing.T) {
 	fakeClient := fake.NewSimpleClientset(
 		createPodWithUID("test-pod", "default", "test-node", "pod-uid-123", corev1.PodQOSBurstable),
@@ -101,7 +215,7 @@ func TestTerminatePod_ActualDelete(t *testing.T) {
 		t.Fatalf("pod should exist before deletion: %v", err)
 	}
 
-	err = c.terminatePod(context.Background(), PodCandidate{
+	_, err = c.terminatePod(context.Background(), PodCandidate{
 		Namespace: "default",
 		Name:      "test-pod",
 	})
@@ -127,7 +241,7 @@ func TestTerminatePod_NonExistent(t *testing.T) {
 		},
 	}
 
-	err := c.terminatePod(context.Background(), PodCandidate{
+	_, err := c.terminatePod(context.Background(), PodCandidate{
 		Namespace: "default",
 		Name:      "nonexistent-pod",
 	})
@@ -179,7 +293,7 @@ func TestScanCgroupsForSwap_QoSFiltering(t *testing.T) {
 		},
 	}
 
-	candidates, err := c.scanCgroupsForSwap()
+	candidates, err := c.scanCgroupsForSwap(context.Background())
 	if err != nil {
 		t.Fatalf("scanCgroupsForSwap() error = %v", err)
 	}
@@ -218,7 +332,7 @@ func TestScanCgroupsForSwap_SwapZeroFiltering(t *testing.T) {
 		},
 	}
 
-	candidates, err := c.scanCgroupsForSwap()
+	candidates, err := c.scanCgroupsForSwap(context.Background())
 	if err != nil {
 		t.Fatalf("scanCgroupsForSwap() error = %v", err)
 	}
@@ -250,7 +364,7 @@ func TestScanCgroupsForSwap_SwapPercentCalculation(t *testing.T) {
 		},
 	}
 
-	candidates, err := c.scanCgroupsForSwap()
+	candidates, err := c.scanCgroupsForSwap(context.Background())
 	if err != nil {
 		t.Fatalf("scanCgroupsForSwap() error = %v", err)
 	}
@@ -259,10 +373,10 @@ func TestScanCgroupsForSwap_SwapPercentCalculation(t *testing.T) {
 		t.Fatalf("scanCgroupsForSwap() returned %d candidates, want 1", len(candidates))
 	}
 
+	// 9.765625% rounds to the 0.1% precision shared by logs and metrics
 	cand := candidates[0]
-	expectedPercent := float64(50<<20) / float64(512<<20) * 100 // ~9.77%
-	if cand.SwapPercent < 9.7 || cand.SwapPercent > 9.8 {
-		t.Errorf("candidate SwapPercent = %.2f, want ~%.2f", cand.SwapPercent, expectedPercent)
+	if cand.SwapPercent != 9.8 {
+		t.Errorf("candidate SwapPercent = %v, want 9.8", cand.SwapPercent)
 	}
 }
 
@@ -284,7 +398,7 @@ func TestScanCgroupsForSwap_MultipleContainersInPod(t *testing.T) {
 		},
 	}
 
-	candidates, err := c.scanCgroupsForSwap()
+	candidates, err := c.scanCgroupsForSwap(context.Background())
 	if err != nil {
 		t.Fatalf("scanCgroupsForSwap() error = %v", err)
 	}
@@ -301,6 +415,142 @@ func TestScanCgroupsForSwap_MultipleContainersInPod(t *testing.T) {
 	}
 }
 
+func TestScanCgroupsForSwap_SkipsScopesWithoutProcesses(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	podSlice := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podaaaa1111_2222_3333_4444_555566667777.slice"
+	// Restarted container (live) and the lingering scope of the container it replaced (empty)
+	live := podSlice + "/cri-containerd-new.scope"
+	stale := podSlice + "/cri-containerd-old.scope"
+	createFakeCgroup(t, tmpDir, live, 50<<20, 512<<20)
+	createFakeCgroup(t, tmpDir, stale, 200<<20, 512<<20)
+	for path, procs := range map[string]string{live: "1234\n1240\n", stale: ""} {
+		if err := os.WriteFile(filepath.Join(tmpDir, path, "cgroup.procs"), []byte(procs), 0644); err != nil {
+			t.Fatalf("Failed to write cgroup.procs: %v", err)
+		}
+	}
+
+	c := &Controller{config: Config{CgroupScanner: cgroup.NewScanner(tmpDir)}}
+
+	candidates, err := c.scanCgroupsForSwap(context.Background())
+	if err != nil {
+		t.Fatalf("scanCgroupsForSwap() error = %v", err)
+	}
+	if len(candidates) != 1 {
+		t.Fatalf("scanCgroupsForSwap() returned %d candidates, want 1", len(candidates))
+	}
+	if candidates[0].SwapBytes != 50<<20 {
+		t.Errorf("SwapBytes = %d, want %d (live scope only)", candidates[0].SwapBytes, 50<<20)
+	}
+}
+
+func TestScanCgroupsForSwap_HugeSwapValues(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	podUID := "aaaa1111_2222_3333_4444_555566667777"
+	const unlimited = 1 << 62 // readMemoryMax sentinel for "max"
+	const nearEightEiB = 7<<60 + 12345
+
+	// Two containers whose swap sum overflows int64
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+podUID+".slice/cri-containerd-abc.scope", nearEightEiB, unlimited)
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+podUID+".slice/cri-containerd-def.scope", nearEightEiB, unlimited)
+
+	c := New(Config{CgroupScanner: cgroup.NewScanner(tmpDir)})
+
+	candidates, err := c.scanCgroupsForSwap(context.Background())
+	if err != nil {
+		t.Fatalf("scanCgroupsForSwap() error = %v", err)
+	}
+	if len(candidates) != 1 {
+		t.Fatalf("scanCgroupsForSwap() returned %d candidates, want 1", len(candidates))
+	}
+
+	cand := candidates[0]
+	if math.IsNaN(cand.SwapPercent) || math.IsInf(cand.SwapPercent, 0) {
+		t.Fatalf("SwapPercent = %v, want finite", cand.SwapPercent)
+	}
+	if cand.SwapPercent < 174.9 || cand.SwapPercent > 175.1 {
+		t.Errorf("SwapPercent = %.2f, want ~175%%", cand.SwapPercent)
+	}
+	if cand.SwapBytes != math.MaxInt64 {
+		t.Errorf("SwapBytes = %d, want saturated at MaxInt64", cand.SwapBytes)
+	}
+
+	// Growth rate from int64 deltas stays exact at this magnitude
+	c.updateSwapHistory([]PodCandidate{{UID: "huge", SwapBytes: nearEightEiB}}, time.Unix(0, 0))
+	grown := []PodCandidate{{UID: "huge", SwapBytes: nearEightEiB + 1024}}
+	c.updateSwapHistory(grown, time.Unix(1, 0))
+	if grown[0].SwapGrowthRate != 1024 {
+		t.Errorf("SwapGrowthRate = %v, want 1024", grown[0].SwapGrowthRate)
+	}
+}
+
+func TestScanCgroupsForSwap_ZeroMemoryMax(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	podUID := "aaaa1111_2222_3333_4444_555566667777"
+	// Cgroup mid-teardown: memory.max reads "0" while swap is still charged
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+podUID+".slice/cri-containerd-abc.scope", 100<<20, 0)
+
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		SwapThresholdPercent: 10.0,
+		WarnThresholdPercent: 0.5,
+		DryRun:               true,
+		CgroupScanner:        cgroup.NewScanner(tmpDir),
+		Metrics:              m,
+		PodInformer: newTestPodInformer(t,
+			createPodWithUID("tearing-down", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		),
+	})
+
+	candidates, err := c.scanCgroupsForSwap(context.Background())
+	if err != nil {
+		t.Fatalf("scanCgroupsForSwap() error = %v", err)
+	}
+	if len(candidates) != 1 {
+		t.Fatalf("scanCgroupsForSwap() returned %d candidates, want 1", len(candidates))
+	}
+	if got := candidates[0].SwapPercent; got != 0 {
+		t.Errorf("SwapPercent = %v, want 0", got)
+	}
+	if score := c.scoreWeights().score(candidates[0]); score != 0 {
+		t.Errorf("score = %v, want 0", score)
+	}
+
+	if err := c.findAndKillOverThreshold(context.Background()); err != nil {
+		t.Fatalf("findAndKillOverThreshold() error = %v", err)
+	}
+	for _, collector := range []prometheus.Collector{m.PodsWarning, m.PodSwapGrowthRate} {
+		if problems := nonFiniteSamples(t, collector); len(problems) > 0 {
+			t.Errorf("non-finite metric samples: %v", problems)
+		}
+	}
+}
+
+// nonFiniteSamples returns descriptions of NaN or Inf gauge values in collector
+func nonFiniteSamples(t *testing.T, collector prometheus.Collector) []string {
+	t.Helper()
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(collector); err != nil {
+		t.Fatalf("Failed to register collector: %v", err)
+	}
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	var problems []string
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			if v := metric.GetGauge().GetValue(); math.IsNaN(v) || math.IsInf(v, 0) {
+				problems = append(problems, fmt.Sprintf("%s=%v", family.GetName(), v))
+			}
+		}
+	}
+	return problems
+}
+
 func TestScanCgroupsForSwap_CRIORuntime(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -316,7 +566,7 @@ func TestScanCgroupsForSwap_CRIORuntime(t *testing.T) {
 		},
 	}
 
-	candidates, err := c.scanCgroupsForSwap()
+	candidates, err := c.scanCgroupsForSwap(context.Background())
 	if err != nil {
 		t.Fatalf("scanCgroupsForSwap() error = %v", err)
 	}
@@ -335,3 +585,1617 @@ func TestScanCgroupsForSwap_CRIORuntime(t *testing.T) {
 // Note: Full integration tests with informer are done via e2e tests.
 // The fake.NewSimpleClientset doesn't provide a proper RESTClient for informers.
 // Tests here focus on unit testing individual components.
+
+func TestReconcile_Metrics(t *testing.T) {
+	m := metrics.NewMetrics("test-node")
+
+	// kubepods.slice missing: the scan fails and the reconcile is counted as an error
+	c := &Controller{
+		config: Config{
+			CgroupScanner: cgroup.NewScanner(t.TempDir()),
+			Metrics:       m,
+		},
+	}
+
+	if err := c.reconcile(context.Background()); err == nil {
+		t.Fatal("reconcile() expected error when kubepods.slice missing")
+	}
+
+	if got := testutil.ToFloat64(m.ReconcilesTotal); got != 1 {
+		t.Errorf("ReconcilesTotal = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.ReconcileErrorsTotal); got != 1 {
+		t.Errorf("ReconcileErrorsTotal = %v, want 1", got)
+	}
+	if got := testutil.CollectAndCount(m.ReconcileDuration); got != 1 {
+		t.Errorf("ReconcileDuration collected %d series, want 1", got)
+	}
+	if got := testutil.CollectAndCount(m.ScanDuration); got != 1 {
+		t.Errorf("ScanDuration collected %d series, want 1", got)
+	}
+}
+
+func TestReconcile_ScanTimeout(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// A FIFO with no writer blocks the open, standing in for a hung cgroup read
+	scope := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podaaaa1111_2222_3333_4444_555566667777.slice/cri-containerd-abc.scope"
+	createFakeCgroup(t, tmpDir, scope, 100<<20, 512<<20)
+	fifo := filepath.Join(tmpDir, scope, "memory.swap.current")
+	if err := os.Remove(fifo); err != nil {
+		t.Fatalf("Failed to remove metric file: %v", err)
+	}
+	if err := syscall.Mkfifo(fifo, 0644); err != nil {
+		t.Fatalf("Failed to create fifo: %v", err)
+	}
+	// Unblock the abandoned scan so it exits before the temp dir is removed
+	t.Cleanup(func() {
+		if f, err := os.OpenFile(fifo, os.O_WRONLY, 0); err == nil {
+			f.Close()
+		}
+	})
+
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		CgroupScanner: cgroup.NewScanner(tmpDir),
+		Metrics:       m,
+		ScanTimeout:   50 * time.Millisecond,
+	})
+
+	start := time.Now()
+	if err := c.reconcile(context.Background()); err == nil {
+		t.Fatal("reconcile() expected error when the scan times out")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("reconcile() took %s, want it bounded by the scan timeout", elapsed)
+	}
+
+	if got := testutil.ToFloat64(m.ScanTimeoutsTotal); got != 1 {
+		t.Errorf("ScanTimeoutsTotal = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.ReconcileErrorsTotal); got != 1 {
+		t.Errorf("ReconcileErrorsTotal = %v, want 1", got)
+	}
+
+	// The abandoned scan is still blocked, so the next poll skips rather than
+	// starting a second scan on the same hung read
+	if err := c.reconcile(context.Background()); err == nil {
+		t.Fatal("reconcile() expected error while the previous scan is blocked")
+	}
+	if got := testutil.ToFloat64(m.ScanTimeoutsTotal); got != 1 {
+		t.Errorf("ScanTimeoutsTotal = %v after a skipped poll, want still 1", got)
+	}
+}
+
+func TestListCandidates_ScanTimeout(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// A FIFO with no writer blocks the open, standing in for a hung cgroup read
+	scope := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podaaaa1111_2222_3333_4444_555566667777.slice/cri-containerd-abc.scope"
+	createFakeCgroup(t, tmpDir, scope, 100<<20, 512<<20)
+	fifo := filepath.Join(tmpDir, scope, "memory.swap.current")
+	if err := os.Remove(fifo); err != nil {
+		t.Fatalf("Failed to remove metric file: %v", err)
+	}
+	if err := syscall.Mkfifo(fifo, 0644); err != nil {
+		t.Fatalf("Failed to create fifo: %v", err)
+	}
+	t.Cleanup(func() {
+		if f, err := os.OpenFile(fifo, os.O_WRONLY, 0); err == nil {
+			f.Close()
+		}
+	})
+
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		CgroupScanner: cgroup.NewScanner(tmpDir),
+		PodInformer:   newTestPodInformer(t),
+		Metrics:       m,
+		ScanTimeout:   50 * time.Millisecond,
+	})
+
+	if _, err := c.ListCandidates(context.Background()); !errors.Is(err, errScanTimeout) {
+		t.Fatalf("ListCandidates() error = %v, want a scan timeout", err)
+	}
+	// The abandoned scan is still blocked: fail fast instead of stacking another
+	if _, err := c.ListCandidates(context.Background()); !errors.Is(err, errScanInFlight) {
+		t.Errorf("second ListCandidates() error = %v, want scan in flight", err)
+	}
+	if c.scanInFlight.Load() {
+		t.Error("ListCandidates marked the reconcile scan in flight")
+	}
+	if got := testutil.ToFloat64(m.ScanTimeoutsTotal); got != 0 {
+		t.Errorf("ScanTimeoutsTotal = %v, want 0 for /candidates scans", got)
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	c := &Controller{
+		config: Config{
+			PollInterval: time.Second,
+		},
+	}
+
+	// Not started yet: healthy
+	if err := c.CheckHealth(); err != nil {
+		t.Errorf("CheckHealth() before start unexpected error: %v", err)
+	}
+
+	// Recent reconcile: healthy
+	c.lastReconcileTime.Store(time.Now().UnixNano())
+	if err := c.CheckHealth(); err != nil {
+		t.Errorf("CheckHealth() after recent reconcile unexpected error: %v", err)
+	}
+
+	// Last reconcile older than 3x poll interval: unhealthy
+	c.lastReconcileTime.Store(time.Now().Add(-4 * time.Second).UnixNano())
+	if err := c.CheckHealth(); err == nil {
+		t.Error("CheckHealth() expected error for stale reconcile")
+	}
+}
+
+func TestTerminatePod_DryRunAudit(t *testing.T) {
+	auditPath := filepath.Join(t.TempDir(), "audit.log")
+	auditLog, err := audit.Open(auditPath)
+	if err != nil {
+		t.Fatalf("audit.Open() error = %v", err)
+	}
+	defer auditLog.Close()
+
+	c := &Controller{
+		config: Config{
+			NodeName:  "test-node",
+			DryRun:    true,
+			K8sClient: fake.NewSimpleClientset(),
+			AuditLog:  auditLog,
+		},
+	}
+
+	_, err = c.terminatePod(context.Background(), PodCandidate{
+		UID:         "pod-uid-123",
+		Namespace:   "default",
+		Name:        "test-pod",
+		SwapBytes:   100 << 20,
+		SwapPercent: 19.5,
+	})
+	if err != nil {
+		t.Fatalf("terminatePod() unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(auditPath)
+	if err != nil {
+		t.Fatalf("Failed to read audit log: %v", err)
+	}
+	var entry audit.Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("Failed to parse audit log %q: %v", data, err)
+	}
+	if entry.Action != audit.ActionDryRun || entry.Name != "test-pod" || entry.Node != "test-node" || entry.SwapBytes != 100<<20 {
+		t.Errorf("audit entry = %+v", entry)
+	}
+}
+
+// newTestPodInformer builds a PodInformer backed by a static indexer holding pods
+func newTestPodInformer(t *testing.T, pods ...*corev1.Pod) *PodInformer {
+	t.Helper()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+		uidIndex:             uidIndexFunc,
+	})
+	for _, pod := range pods {
+		if err := indexer.Add(pod); err != nil {
+			t.Fatalf("Failed to add pod to indexer: %v", err)
+		}
+	}
+	return &PodInformer{indexer: indexer}
+}
+
+func TestListCandidates(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	overUID := "aaaa1111_2222_3333_4444_555566667777"
+	underUID := "bbbb1111_2222_3333_4444_555566667777"
+	protectedUID := "cccc1111_2222_3333_4444_555566667777"
+
+	// 100MB / 512MB = ~19.5% (over threshold)
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+overUID+".slice/cri-containerd-abc.scope", 100<<20, 512<<20)
+	// 1MB / 512MB = ~0.2% (under threshold)
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+underUID+".slice/cri-containerd-def.scope", 1<<20, 512<<20)
+	// 50MB / 512MB = ~9.8% (over threshold, protected)
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+protectedUID+".slice/cri-containerd-ghi.scope", 50<<20, 512<<20)
+
+	c := New(Config{
+		SwapThresholdPercent: 1.0,
+		ProtectedNamespaces:  []string{"kube-system"},
+		CgroupScanner:        cgroup.NewScanner(tmpDir),
+		PodInformer: newTestPodInformer(t,
+			createPodWithUID("over", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+			createPodWithUID("under", "default", "test-node", "bbbb1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+			createPodWithUID("protected", "kube-system", "test-node", "cccc1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		),
+	})
+
+	statuses, err := c.ListCandidates(context.Background())
+	if err != nil {
+		t.Fatalf("ListCandidates() error = %v", err)
+	}
+	if len(statuses) != 3 {
+		t.Fatalf("ListCandidates() returned %d statuses, want 3", len(statuses))
+	}
+
+	// Sorted by swap percent descending
+	want := []struct {
+		name          string
+		overThreshold bool
+		protected     bool
+	}{
+		{"over", true, false},
+		{"protected", true, true},
+		{"under", false, false},
+	}
+	for i, w := range want {
+		got := statuses[i]
+		if got.Name != w.name || got.OverSwapThreshold != w.overThreshold || got.Protected != w.protected {
+			t.Errorf("statuses[%d] = %+v, want name=%s overThreshold=%v protected=%v", i, got, w.name, w.overThreshold, w.protected)
+		}
+	}
+	if statuses[0].SwapBytes != 100<<20 {
+		t.Errorf("statuses[0].SwapBytes = %d, want %d", statuses[0].SwapBytes, 100<<20)
+	}
+}
+
+func TestUpdateSwapHistory_Growth(t *testing.T) {
+	c := &Controller{
+		config: Config{
+			SwapGrowthThreshold: 1 << 20, // 1MB/s
+		},
+	}
+	start := time.Now()
+
+	// First sample: no history, rate unknown
+	candidates := []PodCandidate{
+		{UID: "pod-a", SwapBytes: 10 << 20},
+		{UID: "pod-b", SwapBytes: 10 << 20},
+	}
+	c.updateSwapHistory(candidates, start)
+	for _, cand := range candidates {
+		if cand.SwapGrowthRate != 0 {
+			t.Errorf("first sample %s SwapGrowthRate = %f, want 0", cand.UID, cand.SwapGrowthRate)
+		}
+	}
+
+	// Two seconds later: pod-a grew 4MB (2MB/s), pod-b unchanged
+	candidates = []PodCandidate{
+		{UID: "pod-a", SwapBytes: 14 << 20},
+		{UID: "pod-b", SwapBytes: 10 << 20},
+	}
+	c.updateSwapHistory(candidates, start.Add(2*time.Second))
+	if candidates[0].SwapGrowthRate != 2<<20 {
+		t.Errorf("pod-a SwapGrowthRate = %f, want %d", candidates[0].SwapGrowthRate, 2<<20)
+	}
+	if !c.growthExceeded(candidates[0]) {
+		t.Error("pod-a growth should exceed threshold")
+	}
+	if candidates[1].SwapGrowthRate != 0 {
+		t.Errorf("pod-b SwapGrowthRate = %f, want 0", candidates[1].SwapGrowthRate)
+	}
+	if c.growthExceeded(candidates[1]) {
+		t.Error("pod-b growth should not exceed threshold")
+	}
+
+	// pod-b stops using swap: its history is pruned
+	c.updateSwapHistory([]PodCandidate{{UID: "pod-a", SwapBytes: 14 << 20}}, start.Add(3*time.Second))
+	if _, ok := c.swapHistory["pod-b"]; ok {
+		t.Error("pod-b history should have been pruned")
+	}
+	if len(c.swapHistory) != 1 {
+		t.Errorf("swapHistory has %d entries, want 1", len(c.swapHistory))
+	}
+}
+
+func TestUpdateSwapHistory_DeletesGonePodSeries(t *testing.T) {
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		Metrics: m,
+		PodInformer: newTestPodInformer(t,
+			createPodWithUID("pod-a", "default", "test-node", "uid-a", corev1.PodQOSBurstable),
+			createPodWithUID("pod-b", "default", "test-node", "uid-b", corev1.PodQOSBurstable),
+		),
+	})
+	start := time.Now()
+
+	c.updateSwapHistory([]PodCandidate{
+		{UID: "uid-a", SwapBytes: 1 << 20, SwapPercent: 5},
+		{UID: "uid-b", SwapBytes: 1 << 20, SwapPercent: 7},
+	}, start)
+	if got := testutil.CollectAndCount(m.PodSwapPercent); got != 2 {
+		t.Fatalf("PodSwapPercent series = %d, want 2", got)
+	}
+
+	// pod-b was killed: only its series goes away, pod-a keeps its value
+	c.updateSwapHistory([]PodCandidate{{UID: "uid-a", SwapBytes: 1 << 20, SwapPercent: 6}}, start.Add(time.Second))
+	if got := testutil.CollectAndCount(m.PodSwapPercent); got != 1 {
+		t.Errorf("PodSwapPercent series = %d, want 1", got)
+	}
+	if got := testutil.CollectAndCount(m.PodSwapGrowthRate); got != 1 {
+		t.Errorf("PodSwapGrowthRate series = %d, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.PodSwapPercent.WithLabelValues("default", "pod-a")); got != 6 {
+		t.Errorf("PodSwapPercent{pod-a} = %v, want 6", got)
+	}
+}
+
+func TestGrowthExceeded_Disabled(t *testing.T) {
+	c := &Controller{}
+	if c.growthExceeded(PodCandidate{SwapGrowthRate: 1 << 30}) {
+		t.Error("growthExceeded() should be false when threshold is 0")
+	}
+}
+
+func TestScanCgroupsForSwap_PidsPercent(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	podUID := "aaaa1111_2222_3333_4444_555566667777"
+	podPath := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod" + podUID + ".slice/"
+	createFakeCgroup(t, tmpDir, podPath+"cri-containerd-abc.scope", 1<<20, 512<<20)
+	createFakeCgroup(t, tmpDir, podPath+"cri-containerd-def.scope", 1<<20, 512<<20)
+	for scope, pids := range map[string][2]string{"cri-containerd-abc.scope": {"95", "100"}, "cri-containerd-def.scope": {"10", "max"}} {
+		if err := os.WriteFile(filepath.Join(tmpDir, podPath+scope, "pids.current"), []byte(pids[0]), 0644); err != nil {
+			t.Fatalf("Failed to write pids.current: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, podPath+scope, "pids.max"), []byte(pids[1]), 0644); err != nil {
+			t.Fatalf("Failed to write pids.max: %v", err)
+		}
+	}
+
+	c := New(Config{
+		SwapThresholdPercent: 50.0,
+		PidsThresholdPercent: 90.0,
+		CgroupScanner:        cgroup.NewScanner(tmpDir),
+	})
+
+	candidates, err := c.scanCgroupsForSwap(context.Background())
+	if err != nil {
+		t.Fatalf("scanCgroupsForSwap() error = %v", err)
+	}
+	if len(candidates) != 1 {
+		t.Fatalf("scanCgroupsForSwap() returned %d candidates, want 1", len(candidates))
+	}
+	if candidates[0].PidsPercent != 95 {
+		t.Errorf("PidsPercent = %v, want 95 (max across containers)", candidates[0].PidsPercent)
+	}
+	if !c.pidsExceeded(candidates[0]) {
+		t.Error("pidsExceeded() should be true above the PID threshold")
+	}
+
+	c.config.PidsThresholdPercent = 0
+	if c.pidsExceeded(candidates[0]) {
+		t.Error("pidsExceeded() should be false when threshold is 0")
+	}
+}
+
+func TestScanCgroupsForSwap_IgnoreContainers(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	podUID := "aaaa1111_2222_3333_4444_555566667777"
+	podPath := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod" + podUID + ".slice/"
+	createFakeCgroup(t, tmpDir, podPath+"cri-containerd-app111.scope", 10<<20, 100<<20)
+	createFakeCgroup(t, tmpDir, podPath+"cri-containerd-log222.scope", 90<<20, 100<<20)
+
+	pod := createPodWithUID("web", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable)
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{Name: "app", ContainerID: "containerd://app111"},
+		{Name: "logger", ContainerID: "containerd://log222"},
+	}
+
+	c := New(Config{
+		IgnoreContainers: []string{"logger"},
+		CgroupScanner:    cgroup.NewScanner(tmpDir),
+		PodInformer:      newTestPodInformer(t, pod),
+	})
+
+	candidates, err := c.scanCgroupsForSwap(context.Background())
+	if err != nil {
+		t.Fatalf("scanCgroupsForSwap() error = %v", err)
+	}
+	if len(candidates) != 1 {
+		t.Fatalf("scanCgroupsForSwap() returned %d candidates, want 1", len(candidates))
+	}
+	if candidates[0].SwapPercent != 10 || candidates[0].SwapBytes != 10<<20 {
+		t.Errorf("candidate swap = %v%% / %d bytes, want only the app container (10%% / %d)", candidates[0].SwapPercent, candidates[0].SwapBytes, 10<<20)
+	}
+}
+
+func TestSetSwapNodeFractions(t *testing.T) {
+	procDir := t.TempDir()
+	// 1GiB of node swap
+	if err := os.WriteFile(filepath.Join(procDir, "meminfo"), []byte("SwapTotal:       1048576 kB\n"), 0644); err != nil {
+		t.Fatalf("Failed to write meminfo: %v", err)
+	}
+
+	c := New(Config{
+		NodeFractionThreshold: 0.25,
+		CgroupScanner:         cgroup.NewScannerWithOptions(t.TempDir(), cgroup.Options{ProcPath: procDir}),
+	})
+
+	candidates := []PodCandidate{{UID: "big", SwapBytes: 512 << 20}, {UID: "small", SwapBytes: 64 << 20}}
+	c.setSwapNodeFractions(candidates)
+
+	if candidates[0].SwapNodeFraction != 0.5 || candidates[1].SwapNodeFraction != 0.0625 {
+		t.Errorf("SwapNodeFraction = %v, %v, want 0.5, 0.0625", candidates[0].SwapNodeFraction, candidates[1].SwapNodeFraction)
+	}
+	if !c.nodeFractionExceeded(candidates[0]) || c.nodeFractionExceeded(candidates[1]) {
+		t.Error("nodeFractionExceeded() should be true only for the pod above 0.25")
+	}
+
+	// Unreadable meminfo leaves fractions at 0 so the trigger can't fire
+	c.config.CgroupScanner = cgroup.NewScannerWithOptions(t.TempDir(), cgroup.Options{ProcPath: t.TempDir()})
+	candidates = []PodCandidate{{UID: "big", SwapBytes: 512 << 20}}
+	c.setSwapNodeFractions(candidates)
+	if candidates[0].SwapNodeFraction != 0 {
+		t.Errorf("SwapNodeFraction = %v without meminfo, want 0", candidates[0].SwapNodeFraction)
+	}
+}
+
+func TestUpdateSwapHistory_MemoryHighEvents(t *testing.T) {
+	c := &Controller{config: Config{KillOnMemoryHigh: true}}
+	start := time.Now()
+
+	c.updateSwapHistory([]PodCandidate{{UID: "pod-a", MemoryHighEvents: 40}}, start)
+
+	// Throttled at memory.high since the last pass
+	candidates := []PodCandidate{{UID: "pod-a", MemoryHighEvents: 42}}
+	c.updateSwapHistory(candidates, start.Add(time.Second))
+	if candidates[0].MemoryHighEventsDelta != 2 {
+		t.Errorf("MemoryHighEventsDelta = %d, want 2", candidates[0].MemoryHighEventsDelta)
+	}
+	if !c.memoryHighHit(candidates[0]) {
+		t.Error("memoryHighHit() should be true after counter increase")
+	}
+
+	// Counter reset by a container restart is not a hit
+	candidates = []PodCandidate{{UID: "pod-a", MemoryHighEvents: 1}}
+	c.updateSwapHistory(candidates, start.Add(2*time.Second))
+	if c.memoryHighHit(candidates[0]) {
+		t.Error("memoryHighHit() should be false after counter reset")
+	}
+
+	c.config.KillOnMemoryHigh = false
+	if c.memoryHighHit(PodCandidate{MemoryHighEventsDelta: 5}) {
+		t.Error("memoryHighHit() should be false when disabled")
+	}
+}
+
+func TestUpdateSwapHistory_LimitEvents(t *testing.T) {
+	c := &Controller{
+		config: Config{
+			KillOnSwapLimitEvents: true,
+		},
+	}
+	start := time.Now()
+
+	c.updateSwapHistory([]PodCandidate{{UID: "pod-a", SwapLimitEvents: 5}}, start)
+
+	// Counter increased: limit hit
+	candidates := []PodCandidate{{UID: "pod-a", SwapLimitEvents: 7}}
+	c.updateSwapHistory(candidates, start.Add(time.Second))
+	if candidates[0].SwapLimitEventsDelta != 2 {
+		t.Errorf("SwapLimitEventsDelta = %d, want 2", candidates[0].SwapLimitEventsDelta)
+	}
+	if !c.swapLimitHit(candidates[0]) {
+		t.Error("swapLimitHit() should be true after counter increase")
+	}
+
+	// Counter unchanged: no hit
+	candidates = []PodCandidate{{UID: "pod-a", SwapLimitEvents: 7}}
+	c.updateSwapHistory(candidates, start.Add(2*time.Second))
+	if c.swapLimitHit(candidates[0]) {
+		t.Error("swapLimitHit() should be false when counter unchanged")
+	}
+
+	// Counter reset (container restart): no hit
+	candidates = []PodCandidate{{UID: "pod-a", SwapLimitEvents: 1}}
+	c.updateSwapHistory(candidates, start.Add(3*time.Second))
+	if c.swapLimitHit(candidates[0]) {
+		t.Error("swapLimitHit() should be false after counter reset")
+	}
+}
+
+func TestScanCgroupsForSwap_CustomRuntimePrefix(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	podUID := "aaaa1111_2222_3333_4444_555566667777"
+
+	// Kata Containers scope naming
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+podUID+".slice/kata-abc.scope", 100<<20, 512<<20)
+
+	scanner := cgroup.NewScannerWithOptions(tmpDir, cgroup.Options{
+		RuntimePrefixes: []string{"cri-containerd-", "crio-", "kata-"},
+	})
+	c := &Controller{
+		config: Config{
+			CgroupScanner: scanner,
+		},
+	}
+
+	candidates, err := c.scanCgroupsForSwap(context.Background())
+	if err != nil {
+		t.Fatalf("scanCgroupsForSwap() error = %v", err)
+	}
+
+	if len(candidates) != 1 {
+		t.Fatalf("scanCgroupsForSwap() returned %d candidates, want 1", len(candidates))
+	}
+
+	expectedUID := "aaaa1111-2222-3333-4444-555566667777"
+	if candidates[0].UID != expectedUID {
+		t.Errorf("candidate UID = %s, want %s", candidates[0].UID, expectedUID)
+	}
+}
+
+func TestScanCgroupsForSwap_UnrecognizedMetric(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	podUID := "aaaa1111_2222_3333_4444_555566667777"
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+podUID+".slice/cri-containerd-abc.scope", 100<<20, 512<<20)
+	// Kata scopes aren't in the default runtime prefixes
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+podUID+".slice/kata-def.scope", 100<<20, 512<<20)
+
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		CgroupScanner: cgroup.NewScanner(tmpDir),
+		Metrics:       m,
+	})
+
+	if _, err := c.scanCgroupsForSwap(context.Background()); err != nil {
+		t.Fatalf("scanCgroupsForSwap() error = %v", err)
+	}
+	if got := testutil.ToFloat64(m.UnrecognizedCgroups); got != 1 {
+		t.Errorf("UnrecognizedCgroups = %v, want 1", got)
+	}
+}
+
+func TestScanCgroupsForSwap_CandidatesByQoS(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	burstableUID := "aaaa1111_2222_3333_4444_555566667777"
+	guaranteedUID := "bbbb1111_2222_3333_4444_555566667777"
+	idleUID := "cccc1111_2222_3333_4444_555566667777"
+	// Two containers in the same pod count once
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+burstableUID+".slice/cri-containerd-abc.scope", 100<<20, 512<<20)
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+burstableUID+".slice/cri-containerd-def.scope", 100<<20, 512<<20)
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-pod"+guaranteedUID+".slice/cri-containerd-ghi.scope", 10<<20, 512<<20)
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod"+idleUID+".slice/cri-containerd-jkl.scope", 0, 512<<20)
+
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		CgroupScanner: cgroup.NewScanner(tmpDir),
+		Metrics:       m,
+	})
+
+	candidates, err := c.scanCgroupsForSwap(context.Background())
+	if err != nil {
+		t.Fatalf("scanCgroupsForSwap() error = %v", err)
+	}
+	if len(candidates) != 1 {
+		t.Errorf("scanCgroupsForSwap() returned %d candidates, want 1 (burstable only)", len(candidates))
+	}
+
+	for qos, want := range map[string]float64{"burstable": 1, "guaranteed": 1, "besteffort": 0} {
+		if got := testutil.ToFloat64(m.CandidatesByQoS.WithLabelValues(qos)); got != want {
+			t.Errorf("CandidatesByQoS{qos=%q} = %v, want %v", qos, got, want)
+		}
+	}
+}
+
+func TestFindAndKillOverThreshold_SkipMetrics(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	protectedUID := "aaaa1111_2222_3333_4444_555566667777"
+	terminatingUID := "bbbb1111_2222_3333_4444_555566667777"
+	missingUID := "cccc1111_2222_3333_4444_555566667777"
+
+	for _, uid := range []string{protectedUID, terminatingUID, missingUID} {
+		createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+uid+".slice/cri-containerd-"+uid[:4]+".scope", 100<<20, 512<<20)
+	}
+
+	terminating := createPodWithUID("terminating", "default", "test-node", "bbbb1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable)
+	now := metav1.Now()
+	terminating.DeletionTimestamp = &now
+
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		SwapThresholdPercent: 1.0,
+		DryRun:               true,
+		ProtectedNamespaces:  []string{"kube-system"},
+		CgroupScanner:        cgroup.NewScanner(tmpDir),
+		Metrics:              m,
+		PodInformer: newTestPodInformer(t,
+			createPodWithUID("protected", "kube-system", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+			terminating,
+		),
+	})
+
+	if err := c.findAndKillOverThreshold(context.Background()); err != nil {
+		t.Fatalf("findAndKillOverThreshold() error = %v", err)
+	}
+
+	for _, reason := range []string{skipReasonProtectedNamespace, skipReasonTerminating, skipReasonNotInCache} {
+		if got := testutil.ToFloat64(m.PodsSkippedTotal.WithLabelValues(reason)); got != 1 {
+			t.Errorf("PodsSkippedTotal{reason=%s} = %v, want 1", reason, got)
+		}
+	}
+
+	if got := testutil.ToFloat64(m.PodsOverThreshold); got != 3 {
+		t.Errorf("PodsOverThreshold = %v, want 3", got)
+	}
+	if got := testutil.ToFloat64(m.CandidatePodsCount); got != 3 {
+		t.Errorf("CandidatePodsCount = %v, want 3", got)
+	}
+
+	// Raising the threshold clears the over-threshold gauge on the early return
+	c.config.SwapThresholdPercent = 90.0
+	if err := c.findAndKillOverThreshold(context.Background()); err != nil {
+		t.Fatalf("findAndKillOverThreshold() error = %v", err)
+	}
+	if got := testutil.ToFloat64(m.PodsOverThreshold); got != 0 {
+		t.Errorf("PodsOverThreshold after raising threshold = %v, want 0", got)
+	}
+}
+
+func TestRun_WaitsForInFlightReconcile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	podUID := "aaaa1111_2222_3333_4444_555566667777"
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+podUID+".slice/cri-containerd-abc.scope", 100<<20, 512<<20)
+
+	pod := createPodWithUID("slow-pod", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable)
+	fakeClient := fake.NewSimpleClientset(pod)
+
+	// Block the delete until the test has requested shutdown
+	deleteStarted := make(chan struct{})
+	releaseDelete := make(chan struct{})
+	fakeClient.PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		close(deleteStarted)
+		<-releaseDelete
+		return false, nil, nil
+	})
+
+	c := New(Config{
+		PollInterval:         10 * time.Millisecond,
+		ShutdownTimeout:      5 * time.Second,
+		SwapThresholdPercent: 1.0,
+		K8sClient:            fakeClient,
+		CgroupScanner:        cgroup.NewScanner(tmpDir),
+		PodInformer:          newTestPodInformer(t, pod),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runDone := make(chan struct{})
+	go func() {
+		defer close(runDone)
+		c.Run(ctx)
+	}()
+
+	<-deleteStarted
+	cancel()
+
+	// Run must not return while the delete is still in flight
+	select {
+	case <-runDone:
+		t.Fatal("Run() returned before in-flight reconcile completed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(releaseDelete)
+	<-runDone
+
+	// The delete ran on a context that survived shutdown, so it succeeded
+	if _, err := fakeClient.CoreV1().Pods("default").Get(context.Background(), "slow-pod", metav1.GetOptions{}); err == nil {
+		t.Error("pod still exists, in-flight delete should have completed")
+	}
+}
+
+func TestOwnerSkipReason(t *testing.T) {
+	isController := true
+	withOwner := func(kind string, controller bool) *corev1.Pod {
+		pod := createPodWithUID("p", "default", "test-node", "uid", corev1.PodQOSBurstable)
+		ref := metav1.OwnerReference{Kind: kind, Name: "owner"}
+		if controller {
+			ref.Controller = &isController
+		}
+		pod.OwnerReferences = []metav1.OwnerReference{ref}
+		return pod
+	}
+	standalone := createPodWithUID("p", "default", "test-node", "uid", corev1.PodQOSBurstable)
+
+	tests := []struct {
+		name   string
+		config Config
+		pod    *corev1.Pod
+		want   string
+	}{
+		{"no filters, standalone", Config{}, standalone, ""},
+		{"skip unmanaged, standalone", Config{SkipUnmanaged: true}, standalone, skipReasonUnmanaged},
+		{"skip unmanaged, bare pod owner", Config{SkipUnmanaged: true}, withOwner("Pod", false), skipReasonUnmanaged},
+		{"skip unmanaged, replicaset", Config{SkipUnmanaged: true}, withOwner("ReplicaSet", true), ""},
+		{"skip job kind, job owner", Config{SkipOwnerKinds: []string{"Job"}}, withOwner("Job", true), skipReasonOwnerKind},
+		{"skip job kind, non-controller job owner", Config{SkipOwnerKinds: []string{"Job"}}, withOwner("Job", false), skipReasonOwnerKind},
+		{"skip job kind, replicaset owner", Config{SkipOwnerKinds: []string{"Job"}}, withOwner("ReplicaSet", true), ""},
+		{"protect daemonsets, daemonset owner", Config{ProtectDaemonSetPods: true}, withOwner("DaemonSet", true), skipReasonDaemonSet},
+		{"daemonsets unprotected, daemonset owner", Config{}, withOwner("DaemonSet", true), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(tt.config)
+			if got := c.ownerSkipReason(tt.pod); got != tt.want {
+				t.Errorf("ownerSkipReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcludedCondition(t *testing.T) {
+	now := time.Now()
+	withCondition := func(condType corev1.PodConditionType, status corev1.ConditionStatus, age time.Duration) *corev1.Pod {
+		pod := createPodWithUID("p", "default", "test-node", "uid", corev1.PodQOSBurstable)
+		pod.Status.Conditions = []corev1.PodCondition{{
+			Type:               condType,
+			Status:             status,
+			LastTransitionTime: metav1.NewTime(now.Add(-age)),
+		}}
+		return pod
+	}
+	disruption := Config{ExcludeConditions: []string{"DisruptionTarget"}}
+	notReady := Config{ExcludeNotReadyAfter: time.Minute}
+
+	tests := []struct {
+		name   string
+		config Config
+		pod    *corev1.Pod
+		want   corev1.PodConditionType
+	}{
+		{"no filters, disruption target", Config{}, withCondition(corev1.DisruptionTarget, corev1.ConditionTrue, 0), ""},
+		{"excluded type, true", disruption, withCondition(corev1.DisruptionTarget, corev1.ConditionTrue, 0), corev1.DisruptionTarget},
+		{"excluded type, false", disruption, withCondition(corev1.DisruptionTarget, corev1.ConditionFalse, 0), ""},
+		{"not ready long enough", notReady, withCondition(corev1.PodReady, corev1.ConditionFalse, 2*time.Minute), corev1.PodReady},
+		{"not ready too recently", notReady, withCondition(corev1.PodReady, corev1.ConditionFalse, 30*time.Second), ""},
+		{"ready for a long time", notReady, withCondition(corev1.PodReady, corev1.ConditionTrue, time.Hour), ""},
+		{"not ready, filter disabled", Config{}, withCondition(corev1.PodReady, corev1.ConditionFalse, time.Hour), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(tt.config)
+			if got := c.excludedCondition(tt.pod, now); got != tt.want {
+				t.Errorf("excludedCondition() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateWarnings(t *testing.T) {
+	warnUID := "aaaa1111-2222-3333-4444-555566667777"
+	pod := createPodWithUID("warned", "default", "test-node", types.UID(warnUID), corev1.PodQOSBurstable)
+
+	recorder := record.NewFakeRecorder(10)
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		SwapThresholdPercent: 10.0,
+		WarnThresholdPercent: 5.0,
+		WarnInterval:         time.Minute,
+		EventRecorder:        recorder,
+		Metrics:              m,
+		PodInformer:          newTestPodInformer(t, pod),
+	})
+
+	now := time.Now()
+	inBand := []PodCandidate{{UID: warnUID, SwapPercent: 7.0}}
+
+	c.updateWarnings(inBand, now)
+	if got := len(recorder.Events); got != 1 {
+		t.Fatalf("events after first warning = %d, want 1", got)
+	}
+	if event := <-recorder.Events; !strings.Contains(event, "SoomkillWarning") {
+		t.Errorf("event = %q, want SoomkillWarning", event)
+	}
+	if got := testutil.ToFloat64(m.PodsWarning.WithLabelValues("default", "warned")); got != 7.0 {
+		t.Errorf("PodsWarning = %v, want 7", got)
+	}
+
+	// Within the interval no new event is emitted
+	c.updateWarnings(inBand, now.Add(30*time.Second))
+	if got := len(recorder.Events); got != 0 {
+		t.Errorf("events within warn interval = %d, want 0", got)
+	}
+
+	// After the interval the warning repeats
+	c.updateWarnings(inBand, now.Add(61*time.Second))
+	if got := len(recorder.Events); got != 1 {
+		t.Errorf("events after warn interval = %d, want 1", got)
+	}
+	<-recorder.Events
+
+	// Over the kill threshold is not in the warn band; gauge series is removed
+	c.updateWarnings([]PodCandidate{{UID: warnUID, SwapPercent: 12.0}}, now.Add(62*time.Second))
+	if got := len(recorder.Events); got != 0 {
+		t.Errorf("events above kill threshold = %d, want 0", got)
+	}
+	if got := testutil.CollectAndCount(m.PodsWarning); got != 0 {
+		t.Errorf("PodsWarning series = %d, want 0", got)
+	}
+}
+
+func TestUpdateWarnings_SkipsOtherTriggers(t *testing.T) {
+	warnUID := "aaaa1111-2222-3333-4444-555566667777"
+	pod := createPodWithUID("warned", "default", "test-node", types.UID(warnUID), corev1.PodQOSBurstable)
+
+	recorder := record.NewFakeRecorder(10)
+	c := New(Config{
+		SwapThresholdPercent:  10.0,
+		WarnThresholdPercent:  5.0,
+		WarnInterval:          time.Minute,
+		PidsThresholdPercent:  90,
+		NodeFractionThreshold: 0.5,
+		KillOnMemoryHigh:      true,
+		EventRecorder:         recorder,
+		PodInformer:           newTestPodInformer(t, pod),
+	})
+
+	// In the warn band, but each is killed this tick by a trigger other than swap percent
+	for _, cand := range []PodCandidate{
+		{UID: warnUID, SwapPercent: 7.0, PidsPercent: 95},
+		{UID: warnUID, SwapPercent: 7.0, SwapNodeFraction: 0.6},
+		{UID: warnUID, SwapPercent: 7.0, MemoryHighEventsDelta: 1},
+	} {
+		c.updateWarnings([]PodCandidate{cand}, time.Now())
+		if got := len(recorder.Events); got != 0 {
+			t.Errorf("events for %+v = %d, want 0", cand, got)
+			<-recorder.Events
+		}
+	}
+}
+
+func TestUpdateNodeTaint(t *testing.T) {
+	procDir := t.TempDir()
+	writeSwaps := func(usedKiB int) {
+		content := "Filename\t\t\t\tType\t\tSize\t\tUsed\t\tPriority\n" +
+			fmt.Sprintf("/swapfile                               file\t\t1000\t\t%d\t\t-2\n", usedKiB)
+		if err := os.WriteFile(filepath.Join(procDir, "swaps"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write swaps: %v", err)
+		}
+	}
+	hasTaint := func(client *fake.Clientset) bool {
+		node, err := client.CoreV1().Nodes().Get(context.Background(), "test-node", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get node: %v", err)
+		}
+		for _, taint := range node.Spec.Taints {
+			if taint.Key == SwapPressureTaintKey && taint.Effect == corev1.TaintEffectNoSchedule {
+				return true
+			}
+		}
+		return false
+	}
+
+	fakeClient := fake.NewSimpleClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+		Spec: corev1.NodeSpec{
+			Taints: []corev1.Taint{{Key: "other", Effect: corev1.TaintEffectNoExecute}},
+		},
+	})
+
+	scanner := cgroup.NewScannerWithOptions(t.TempDir(), cgroup.Options{ProcPath: procDir})
+	c := New(Config{
+		NodeName:              "test-node",
+		TaintOnPressure:       true,
+		SwapActivationPercent: 50,
+		TaintAfter:            time.Minute,
+		K8sClient:             fakeClient,
+		CgroupScanner:         scanner,
+	})
+
+	ctx := context.Background()
+	now := time.Now()
+
+	// Pressure starts but has not lasted long enough
+	writeSwaps(800)
+	c.updateNodeTaint(ctx, now)
+	if hasTaint(fakeClient) {
+		t.Error("node tainted before --taint-after elapsed")
+	}
+
+	// Sustained pressure taints the node
+	c.updateNodeTaint(ctx, now.Add(time.Minute))
+	if !hasTaint(fakeClient) {
+		t.Error("node not tainted after sustained pressure")
+	}
+
+	// Pressure clears and the taint is removed, leaving other taints alone
+	writeSwaps(100)
+	c.updateNodeTaint(ctx, now.Add(2*time.Minute))
+	if hasTaint(fakeClient) {
+		t.Error("node still tainted after pressure cleared")
+	}
+	node, _ := fakeClient.CoreV1().Nodes().Get(ctx, "test-node", metav1.GetOptions{})
+	if len(node.Spec.Taints) != 1 || node.Spec.Taints[0].Key != "other" {
+		t.Errorf("node taints = %+v, want only 'other'", node.Spec.Taints)
+	}
+}
+
+func TestGetPodByUID_RawUnderscoreFallback(t *testing.T) {
+	informer := newTestPodInformer(t,
+		createPodWithUID("standard", "default", "test-node", "12345678-1234-1234-1234-123456789abc", corev1.PodQOSBurstable),
+		createPodWithUID("nonstandard", "default", "test-node", "abc_def_123", corev1.PodQOSBestEffort),
+	)
+
+	if pod := informer.GetPodByUID("12345678-1234-1234-1234-123456789abc"); pod == nil || pod.Name != "standard" {
+		t.Errorf("GetPodByUID(standard) = %v, want standard", pod)
+	}
+	// ExtractPodUID turns podabc_def_123 into abc-def-123
+	if pod := informer.GetPodByUID("abc-def-123"); pod == nil || pod.Name != "nonstandard" {
+		t.Errorf("GetPodByUID(abc-def-123) = %v, want nonstandard", pod)
+	}
+	if pod := informer.GetPodByUID("missing-uid"); pod != nil {
+		t.Errorf("GetPodByUID(missing-uid) = %v, want nil", pod)
+	}
+}
+
+func TestValidateEventReason(t *testing.T) {
+	tests := []struct {
+		reason  string
+		wantErr bool
+	}{
+		{DefaultEventReason, false},
+		{"SwapEvicted", false},
+		{"OOMSoft2", false},
+		{"", true},
+		{"soomkilled", true},
+		{"Swap Evicted", true},
+		{"Swap-Evicted", true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateEventReason(tt.reason); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateEventReason(%q) error = %v, wantErr %v", tt.reason, err, tt.wantErr)
+		}
+	}
+}
+
+func TestTerminatePod_AnnotatesOwner(t *testing.T) {
+	isController := true
+	pod := createPodWithUID("web-abc-x1", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable)
+	pod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-abc", Controller: &isController}}
+	rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-abc", Namespace: "default"}}
+	fakeClient := fake.NewSimpleClientset(pod, rs)
+
+	c := New(Config{
+		NodeName:      "test-node",
+		AnnotateOwner: true,
+		K8sClient:     fakeClient,
+		PodInformer:   newTestPodInformer(t, pod),
+	})
+
+	cand := PodCandidate{UID: string(pod.UID), Namespace: "default", Name: pod.Name, SwapPercent: 12.5}
+	if _, err := c.terminatePod(context.Background(), cand); err != nil {
+		t.Fatalf("terminatePod() error = %v", err)
+	}
+
+	got, err := fakeClient.AppsV1().ReplicaSets("default").Get(context.Background(), "web-abc", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get replicaset: %v", err)
+	}
+	var kill lastKill
+	if err := json.Unmarshal([]byte(got.Annotations[LastKillAnnotation]), &kill); err != nil {
+		t.Fatalf("Failed to parse %s annotation %q: %v", LastKillAnnotation, got.Annotations[LastKillAnnotation], err)
+	}
+	if kill.Pod != pod.Name || kill.Node != "test-node" || kill.SwapPercent != 12.5 || kill.Time.IsZero() {
+		t.Errorf("last-kill annotation = %+v", kill)
+	}
+
+	if _, err := fakeClient.CoreV1().Pods("default").Get(context.Background(), pod.Name, metav1.GetOptions{}); err == nil {
+		t.Error("pod still exists after terminatePod()")
+	}
+}
+
+func TestFindAndKillOverThreshold_UnlimitedMemorySwapMax(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Neither pod has a memory limit. Under LimitedSwap kubelet still gives
+	// "capped" a finite swap.max, which becomes its swap percent denominator.
+	pods := []*corev1.Pod{
+		createPodWithUID("capped", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		createPodWithUID("uncapped", "default", "test-node", "bbbb1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+	}
+	swapMax := map[string]string{"capped": fmt.Sprintf("%d", 100<<20), "uncapped": "max"}
+	for _, pod := range pods {
+		uid := strings.ReplaceAll(string(pod.UID), "-", "_")
+		scope := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod" + uid + ".slice/cri-containerd-" + uid[:4] + ".scope"
+		createFakeCgroup(t, tmpDir, scope, 50<<20, 0)
+		for name, content := range map[string]string{"memory.max": "max", "memory.swap.max": swapMax[pod.Name]} {
+			if err := os.WriteFile(filepath.Join(tmpDir, scope, name), []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", name, err)
+			}
+		}
+	}
+
+	fakeClient := fake.NewSimpleClientset(pods[0], pods[1])
+	c := New(Config{
+		SwapThresholdPercent: 10.0,
+		K8sClient:            fakeClient,
+		CgroupScanner:        cgroup.NewScanner(tmpDir),
+		PodInformer:          newTestPodInformer(t, pods...),
+	})
+
+	if err := c.findAndKillOverThreshold(context.Background()); err != nil {
+		t.Fatalf("findAndKillOverThreshold() error = %v", err)
+	}
+
+	// 50MB of a 100MB swap.max is 50%; with no swap.max the sentinel reads ~0%
+	for _, pod := range pods {
+		_, err := fakeClient.CoreV1().Pods("default").Get(context.Background(), pod.Name, metav1.GetOptions{})
+		if gone := err != nil; gone != (pod.Name == "capped") {
+			t.Errorf("pod %s killed = %v, want only capped killed", pod.Name, gone)
+		}
+	}
+}
+
+func TestFindAndKillOverThreshold_KillTopN(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Pods swap 300MB, 200MB and 100MB of 512MB respectively
+	pods := []*corev1.Pod{
+		createPodWithUID("worst", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		createPodWithUID("middle", "default", "test-node", "bbbb1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		createPodWithUID("mild", "default", "test-node", "cccc1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+	}
+	for i, pod := range pods {
+		uid := strings.ReplaceAll(string(pod.UID), "-", "_")
+		createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+uid+".slice/cri-containerd-"+uid[:4]+".scope", int64(3-i)*100<<20, 512<<20)
+	}
+
+	fakeClient := fake.NewSimpleClientset(pods[0], pods[1], pods[2])
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		SwapThresholdPercent: 1.0,
+		KillTopN:             1,
+		K8sClient:            fakeClient,
+		CgroupScanner:        cgroup.NewScanner(tmpDir),
+		Metrics:              m,
+		PodInformer:          newTestPodInformer(t, pods...),
+	})
+
+	if err := c.findAndKillOverThreshold(context.Background()); err != nil {
+		t.Fatalf("findAndKillOverThreshold() error = %v", err)
+	}
+
+	remaining, err := fakeClient.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list pods: %v", err)
+	}
+	var names []string
+	for _, pod := range remaining.Items {
+		names = append(names, pod.Name)
+	}
+	if len(names) != 2 || strings.Contains(strings.Join(names, ","), "worst") {
+		t.Errorf("remaining pods = %v, want middle and mild", names)
+	}
+
+	if got := testutil.ToFloat64(m.PodsKilledTotal); got != 1 {
+		t.Errorf("PodsKilledTotal = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.PodsSkippedTotal.WithLabelValues(skipReasonKillLimit)); got != 2 {
+		t.Errorf("PodsSkippedTotal{reason=%s} = %v, want 2", skipReasonKillLimit, got)
+	}
+}
+
+func TestFindAndKillOverThreshold_KillTopNDryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	pods := []*corev1.Pod{
+		createPodWithUID("worst", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		createPodWithUID("middle", "default", "test-node", "bbbb1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		createPodWithUID("mild", "default", "test-node", "cccc1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+	}
+	for i, pod := range pods {
+		uid := strings.ReplaceAll(string(pod.UID), "-", "_")
+		createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+uid+".slice/cri-containerd-"+uid[:4]+".scope", int64(3-i)*100<<20, 512<<20)
+	}
+
+	recorder := &decisionRecorder{}
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		SwapThresholdPercent: 1.0,
+		KillTopN:             1,
+		DryRun:               true,
+		K8sClient:            fake.NewSimpleClientset(pods[0], pods[1], pods[2]),
+		CgroupScanner:        cgroup.NewScanner(tmpDir),
+		Metrics:              m,
+		PodInformer:          newTestPodInformer(t, pods...),
+		DecisionSink:         recorder,
+	})
+
+	if err := c.findAndKillOverThreshold(context.Background()); err != nil {
+		t.Fatalf("findAndKillOverThreshold() error = %v", err)
+	}
+
+	// Dry-run reports the one pod a live pass would kill, not every pod over threshold
+	if got := testutil.ToFloat64(m.PodsWouldKill); got != 1 {
+		t.Errorf("PodsWouldKill = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.PodsSkippedTotal.WithLabelValues(skipReasonKillLimit)); got != 2 {
+		t.Errorf("PodsSkippedTotal{reason=%s} = %v, want 2", skipReasonKillLimit, got)
+	}
+	for _, d := range recorder.decisions {
+		if d.Action == DecisionDryRun && d.Name != "worst" {
+			t.Errorf("dry-run decision for %s, want only worst", d.Name)
+		}
+	}
+}
+
+func TestFindAndKillOverThreshold_TieBreakByUID(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Equal swap percent: the lower UID is killed first, whatever the scan order
+	pods := []*corev1.Pod{
+		createPodWithUID("second", "default", "test-node", "bbbb1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		createPodWithUID("first", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+	}
+	for _, pod := range pods {
+		uid := strings.ReplaceAll(string(pod.UID), "-", "_")
+		createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+uid+".slice/cri-containerd-"+uid[:4]+".scope", 100<<20, 512<<20)
+	}
+
+	for run := 0; run < 5; run++ {
+		fakeClient := fake.NewSimpleClientset(pods[0], pods[1])
+		c := New(Config{
+			SwapThresholdPercent: 1.0,
+			KillTopN:             1,
+			K8sClient:            fakeClient,
+			CgroupScanner:        cgroup.NewScanner(tmpDir),
+			PodInformer:          newTestPodInformer(t, pods...),
+		})
+
+		if err := c.findAndKillOverThreshold(context.Background()); err != nil {
+			t.Fatalf("findAndKillOverThreshold() error = %v", err)
+		}
+
+		if _, err := fakeClient.CoreV1().Pods("default").Get(context.Background(), "first", metav1.GetOptions{}); err == nil {
+			t.Fatalf("run %d: pod with lower UID was not killed first", run)
+		}
+		if _, err := fakeClient.CoreV1().Pods("default").Get(context.Background(), "second", metav1.GetOptions{}); err != nil {
+			t.Fatalf("run %d: pod with higher UID was killed: %v", run, err)
+		}
+	}
+}
+
+func TestFindAndKillOverThreshold_ProtectSelector(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	critical := createPodWithUID("critical", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable)
+	critical.Labels = map[string]string{"critical": "true"}
+	pods := []*corev1.Pod{
+		critical,
+		createPodWithUID("system", "kube-system", "test-node", "bbbb1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		createPodWithUID("ordinary", "default", "test-node", "cccc1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+	}
+	for _, pod := range pods {
+		uid := strings.ReplaceAll(string(pod.UID), "-", "_")
+		createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+uid+".slice/cri-containerd-"+uid[:4]+".scope", 100<<20, 512<<20)
+	}
+
+	fakeClient := fake.NewSimpleClientset(pods[0], pods[1], pods[2])
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		SwapThresholdPercent: 1.0,
+		ProtectedNamespaces:  []string{"kube-system"},
+		ProtectSelector:      labels.SelectorFromSet(labels.Set{"critical": "true"}),
+		K8sClient:            fakeClient,
+		CgroupScanner:        cgroup.NewScanner(tmpDir),
+		Metrics:              m,
+		PodInformer:          newTestPodInformer(t, pods...),
+	})
+
+	if err := c.findAndKillOverThreshold(context.Background()); err != nil {
+		t.Fatalf("findAndKillOverThreshold() error = %v", err)
+	}
+
+	// Either rule protects a pod; only the ordinary pod is deleted
+	if _, err := fakeClient.CoreV1().Pods("default").Get(context.Background(), "critical", metav1.GetOptions{}); err != nil {
+		t.Errorf("critical pod was deleted: %v", err)
+	}
+	if _, err := fakeClient.CoreV1().Pods("kube-system").Get(context.Background(), "system", metav1.GetOptions{}); err != nil {
+		t.Errorf("kube-system pod was deleted: %v", err)
+	}
+	if _, err := fakeClient.CoreV1().Pods("default").Get(context.Background(), "ordinary", metav1.GetOptions{}); err == nil {
+		t.Error("ordinary pod was not deleted")
+	}
+
+	for _, reason := range []string{skipReasonProtectLabel, skipReasonProtectedNamespace} {
+		if got := testutil.ToFloat64(m.PodsSkippedTotal.WithLabelValues(reason)); got != 1 {
+			t.Errorf("PodsSkippedTotal{reason=%s} = %v, want 1", reason, got)
+		}
+	}
+}
+
+func TestUpdateInformerMetrics(t *testing.T) {
+	m := metrics.NewMetrics("test-node")
+	informer := newTestPodInformer(t,
+		createPodWithUID("pod-a", "default", "test-node", "uid-a", corev1.PodQOSBurstable),
+		createPodWithUID("pod-b", "default", "test-node", "uid-b", corev1.PodQOSBurstable),
+	)
+	c := New(Config{Metrics: m, PodInformer: informer})
+
+	c.updateInformerMetrics()
+	if got := testutil.ToFloat64(m.InformerPodsCached); got != 2 {
+		t.Errorf("InformerPodsCached = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.InformerLastResyncTime); got != 0 {
+		t.Errorf("InformerLastResyncTime = %v before any resync, want 0", got)
+	}
+
+	resync := time.Unix(1700000000, 0)
+	informer.lastResync.Store(resync.UnixNano())
+	c.updateInformerMetrics()
+	if got := testutil.ToFloat64(m.InformerLastResyncTime); got != 1700000000 {
+		t.Errorf("InformerLastResyncTime = %v, want 1700000000", got)
+	}
+}
+
+func TestVerifyNodeSelector(t *testing.T) {
+	onNode := func(name, node string) *corev1.Pod {
+		return createPodWithUID(name, "default", node, types.UID("uid-"+name), corev1.PodQOSBurstable)
+	}
+
+	// The fake clientset ignores field selectors, modelling a proxy that drops them
+	ignored := fake.NewSimpleClientset(onNode("local", "test-node"), onNode("remote", "other-node"))
+	if err := VerifyNodeSelector(context.Background(), ignored, "test-node"); err == nil {
+		t.Error("VerifyNodeSelector() expected error when pods from other nodes are listed")
+	} else if !strings.Contains(err.Error(), "default/remote") {
+		t.Errorf("VerifyNodeSelector() error = %v, want it to name default/remote", err)
+	}
+
+	honored := fake.NewSimpleClientset(onNode("local", "test-node"))
+	if err := VerifyNodeSelector(context.Background(), honored, "test-node"); err != nil {
+		t.Errorf("VerifyNodeSelector() error = %v, want nil", err)
+	}
+}
+
+func TestPodFieldSelector(t *testing.T) {
+	running := createPodWithUID("running", "default", "test-node", "uid-running", corev1.PodQOSBurstable)
+	running.Status.Phase = corev1.PodRunning
+	done := createPodWithUID("done", "default", "test-node", "uid-done", corev1.PodQOSBurstable)
+	done.Status.Phase = corev1.PodSucceeded
+
+	podFields := func(pod *corev1.Pod) fields.Set {
+		return fields.Set{"spec.nodeName": pod.Spec.NodeName, "status.phase": string(pod.Status.Phase)}
+	}
+
+	all := podFieldSelector("test-node", false)
+	if all.String() != "spec.nodeName=test-node" {
+		t.Errorf("podFieldSelector(false) = %q, want spec.nodeName=test-node", all.String())
+	}
+	if !all.Matches(podFields(done)) {
+		t.Error("podFieldSelector(false) should match completed pods")
+	}
+
+	runningOnly := podFieldSelector("test-node", true)
+	if !runningOnly.Matches(podFields(running)) {
+		t.Error("podFieldSelector(true) should match running pods on the node")
+	}
+	if runningOnly.Matches(podFields(done)) {
+		t.Error("podFieldSelector(true) should not match completed pods")
+	}
+}
+
+func TestTerminatePod_LastKillInfo(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		createPodWithUID("first", "default", "test-node", "uid-first", corev1.PodQOSBurstable),
+		createPodWithUID("second", "other", "test-node", "uid-second", corev1.PodQOSBurstable),
+	)
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		K8sClient: fakeClient,
+		Metrics:   m,
+	})
+
+	for _, cand := range []PodCandidate{
+		{UID: "uid-first", Namespace: "default", Name: "first"},
+		{UID: "uid-second", Namespace: "other", Name: "second"},
+	} {
+		if _, err := c.terminatePod(context.Background(), cand); err != nil {
+			t.Fatalf("terminatePod(%s) error = %v", cand.Name, err)
+		}
+	}
+
+	// Only the latest kill keeps a series
+	if got := testutil.CollectAndCount(m.LastKillInfo); got != 1 {
+		t.Fatalf("LastKillInfo collected %d series, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.LastKillInfo.WithLabelValues("other", "second")); got == 0 {
+		t.Error("LastKillInfo{other/second} = 0, want the kill timestamp")
+	}
+}
+
+func TestTerminatePod_KillToRemoval(t *testing.T) {
+	pod := createPodWithUID("web", "default", "test-node", "uid-web", corev1.PodQOSBurstable)
+	informer := newTestPodInformer(t, pod)
+	c := New(Config{
+		K8sClient:   fake.NewSimpleClientset(pod),
+		PodInformer: informer,
+		Metrics:     metrics.NewMetrics("test-node"),
+	})
+
+	if _, err := c.terminatePod(context.Background(), PodCandidate{UID: "uid-web", Namespace: "default", Name: "web"}); err != nil {
+		t.Fatalf("terminatePod() error = %v", err)
+	}
+	if _, ok := c.pendingKills["uid-web"]; !ok {
+		t.Fatal("terminatePod() did not track the kill for removal")
+	}
+
+	// Deletes of pods the controller didn't kill leave tracked kills alone
+	informer.podDeleted("uid-other")
+	if len(c.pendingKills) != 1 {
+		t.Fatalf("%d kills tracked after an unrelated delete, want 1", len(c.pendingKills))
+	}
+	informer.podDeleted("uid-web")
+	if len(c.pendingKills) != 0 {
+		t.Errorf("%d kills still tracked after removal, want 0", len(c.pendingKills))
+	}
+}
+
+func TestFindAndKillOverThreshold_MixedRuntimes(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	pods := []*corev1.Pod{
+		createPodWithUID("on-containerd", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		createPodWithUID("on-crio", "default", "test-node", "bbbb1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+	}
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podaaaa1111_2222_3333_4444_555566667777.slice/cri-containerd-abc.scope", 100<<20, 512<<20)
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podbbbb1111_2222_3333_4444_555566667777.slice/crio-def.scope", 100<<20, 512<<20)
+
+	scanner := cgroup.NewScanner(tmpDir)
+	scanner.DetectRuntimes()
+
+	fakeClient := fake.NewSimpleClientset(pods[0], pods[1])
+	c := New(Config{
+		SwapThresholdPercent: 1.0,
+		K8sClient:            fakeClient,
+		CgroupScanner:        scanner,
+		PodInformer:          newTestPodInformer(t, pods...),
+	})
+
+	candidates, err := c.scanCgroupsForSwap(context.Background())
+	if err != nil {
+		t.Fatalf("scanCgroupsForSwap() error = %v", err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("scanCgroupsForSwap() returned %d candidates, want one per runtime", len(candidates))
+	}
+
+	if err := c.findAndKillOverThreshold(context.Background()); err != nil {
+		t.Fatalf("findAndKillOverThreshold() error = %v", err)
+	}
+	for _, pod := range pods {
+		if _, err := fakeClient.CoreV1().Pods("default").Get(context.Background(), pod.Name, metav1.GetOptions{}); err == nil {
+			t.Errorf("pod %s was not killed", pod.Name)
+		}
+	}
+}
+
+func TestFindAndKillOverThreshold_SortByFootprint(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// "hot" swaps the highest share of its small limit; "big" swaps less of
+	// a large limit but holds far more memory
+	pods := []*corev1.Pod{
+		createPodWithUID("hot", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		createPodWithUID("big", "default", "test-node", "bbbb1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+	}
+	hotScope := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podaaaa1111_2222_3333_4444_555566667777.slice/cri-containerd-abc.scope"
+	bigScope := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podbbbb1111_2222_3333_4444_555566667777.slice/cri-containerd-def.scope"
+	createFakeCgroup(t, tmpDir, hotScope, 100<<20, 256<<20)
+	createFakeCgroup(t, tmpDir, bigScope, 200<<20, 8<<30)
+	if err := os.WriteFile(filepath.Join(tmpDir, bigScope, "memory.current"), []byte(fmt.Sprintf("%d", 6<<30)), 0644); err != nil {
+		t.Fatalf("Failed to write memory.current: %v", err)
+	}
+
+	tests := []struct {
+		sortBy string
+		killed string
+	}{
+		{SortByScore, "hot"},
+		{SortByFootprint, "big"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			fakeClient := fake.NewSimpleClientset(pods[0], pods[1])
+			c := New(Config{
+				SwapThresholdPercent: 1.0,
+				KillTopN:             1,
+				SortBy:               tt.sortBy,
+				K8sClient:            fakeClient,
+				CgroupScanner:        cgroup.NewScanner(tmpDir),
+				PodInformer:          newTestPodInformer(t, pods...),
+			})
+
+			if err := c.findAndKillOverThreshold(context.Background()); err != nil {
+				t.Fatalf("findAndKillOverThreshold() error = %v", err)
+			}
+			for _, pod := range pods {
+				_, err := fakeClient.CoreV1().Pods("default").Get(context.Background(), pod.Name, metav1.GetOptions{})
+				if gone := err != nil; gone != (pod.Name == tt.killed) {
+					t.Errorf("pod %s killed = %v, want only %s killed", pod.Name, gone, tt.killed)
+				}
+			}
+		})
+	}
+}
+
+func TestScanCgroupsForSwap_MaxCandidates(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Pod i swaps i MB of a 512MB limit, so the top 10 are pods 41-50
+	for i := 1; i <= 50; i++ {
+		podUID := fmt.Sprintf("%08d_2222_3333_4444_555566667777", i)
+		createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+podUID+".slice/cri-containerd-abc.scope", int64(i)<<20, 512<<20)
+	}
+	// A low-swap first container must not get pod 5 evicted once its second container is added
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod00000005_2222_3333_4444_555566667777.slice/cri-containerd-def.scope", 100<<20, 512<<20)
+
+	c := &Controller{
+		config: Config{
+			CgroupScanner: cgroup.NewScanner(tmpDir),
+			MaxCandidates: 10,
+		},
+	}
+
+	candidates, err := c.scanCgroupsForSwap(context.Background())
+	if err != nil {
+		t.Fatalf("scanCgroupsForSwap() error = %v", err)
+	}
+	if len(candidates) != 10 {
+		t.Fatalf("scanCgroupsForSwap() returned %d candidates, want the cap of 10", len(candidates))
+	}
+	if !c.candidatesCapped {
+		t.Error("candidatesCapped = false after exceeding the cap, want true")
+	}
+
+	want := map[string]bool{"00000005-2222-3333-4444-555566667777": true}
+	for i := 42; i <= 50; i++ {
+		want[fmt.Sprintf("%08d-2222-3333-4444-555566667777", i)] = true
+	}
+	for _, cand := range candidates {
+		if !want[cand.UID] {
+			t.Errorf("retained %s (swap %.2f%%), want only the 10 highest-swap pods", cand.UID, cand.SwapPercent)
+		}
+	}
+}
+
+func TestScanSwap_DroppedCountsPods(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Three two-container pods under a cap of one: the top pod is kept, and
+	// each of the other two counts once however many containers were dropped
+	for i := 1; i <= 3; i++ {
+		podPath := fmt.Sprintf("kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod%08d_2222_3333_4444_555566667777.slice/", i)
+		createFakeCgroup(t, tmpDir, podPath+"cri-containerd-abc.scope", int64(i)<<20, 512<<20)
+		createFakeCgroup(t, tmpDir, podPath+"cri-containerd-def.scope", int64(i)<<20, 512<<20)
+	}
+
+	c := &Controller{
+		config: Config{
+			CgroupScanner: cgroup.NewScanner(tmpDir),
+			PodInformer:   newTestPodInformer(t),
+			MaxCandidates: 1,
+		},
+	}
+
+	result, err := c.scanSwap(context.Background())
+	if err != nil {
+		t.Fatalf("scanSwap() error = %v", err)
+	}
+	if result.Dropped != 2 {
+		t.Errorf("Dropped = %d, want 2 pods", result.Dropped)
+	}
+
+	// ListCandidates serves /candidates off the reconcile loop and must not touch its state
+	if _, err := c.ListCandidates(context.Background()); err != nil {
+		t.Fatalf("ListCandidates() error = %v", err)
+	}
+	if c.candidatesCapped {
+		t.Error("candidatesCapped = true after ListCandidates, want it left to the reconcile scan")
+	}
+}
+
+func TestScanCgroupsForSwap_SwapOfLimitPercent(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	podUID := "aaaa1111_2222_3333_4444_555566667777"
+	podPath := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod" + podUID + ".slice/"
+	// 3MB of a 4MB swap allocation = 75%, alongside an unlimited-swap container
+	createFakeCgroup(t, tmpDir, podPath+"cri-containerd-abc.scope", 3<<20, 512<<20)
+	createFakeCgroup(t, tmpDir, podPath+"cri-containerd-def.scope", 1<<20, 512<<20)
+	if err := os.WriteFile(filepath.Join(tmpDir, podPath+"cri-containerd-abc.scope", "memory.swap.max"), []byte(fmt.Sprintf("%d", 4<<20)), 0644); err != nil {
+		t.Fatalf("Failed to write memory.swap.max: %v", err)
+	}
+
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		CgroupScanner: cgroup.NewScanner(tmpDir),
+		Metrics:       m,
+		PodInformer: newTestPodInformer(t,
+			createPodWithUID("web", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		),
+	})
+
+	candidates, err := c.scanCgroupsForSwap(context.Background())
+	if err != nil {
+		t.Fatalf("scanCgroupsForSwap() error = %v", err)
+	}
+	if len(candidates) != 1 {
+		t.Fatalf("scanCgroupsForSwap() returned %d candidates, want 1", len(candidates))
+	}
+	if candidates[0].SwapOfLimitPercent != 75 {
+		t.Errorf("SwapOfLimitPercent = %v, want 75 (max across containers with a swap limit)", candidates[0].SwapOfLimitPercent)
+	}
+
+	c.updateSwapHistory(candidates, time.Now())
+	if got := testutil.ToFloat64(m.PodSwapOfLimit.WithLabelValues("default", "web")); got != 75 {
+		t.Errorf("PodSwapOfLimit{web} = %v, want 75", got)
+	}
+
+	// Without a swap limit the pod has no series
+	candidates[0].SwapOfLimitPercent = 0
+	c.updateSwapHistory(candidates, time.Now())
+	if got := testutil.CollectAndCount(m.PodSwapOfLimit); got != 0 {
+		t.Errorf("PodSwapOfLimit series = %d, want 0 for a pod without a swap limit", got)
+	}
+}
+
+func TestScanCgroupsForSwap_ReadErrorsMetric(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	base := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"
+	for _, uid := range []string{"aaaa1111_2222_3333_4444_555566667777", "bbbb1111_2222_3333_4444_555566667777", "cccc1111_2222_3333_4444_555566667777"} {
+		createFakeCgroup(t, tmpDir, base+uid+".slice/cri-containerd-abc.scope", 1<<20, 512<<20)
+	}
+	// Two pods whose PSI file is gone, one with a corrupt memory.max
+	for _, uid := range []string{"aaaa1111_2222_3333_4444_555566667777", "bbbb1111_2222_3333_4444_555566667777"} {
+		if err := os.Remove(filepath.Join(tmpDir, base+uid+".slice/cri-containerd-abc.scope", "memory.pressure")); err != nil {
+			t.Fatalf("Failed to remove memory.pressure: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, base+"cccc1111_2222_3333_4444_555566667777.slice/cri-containerd-abc.scope", "memory.max"), []byte("garbage"), 0644); err != nil {
+		t.Fatalf("Failed to write memory.max: %v", err)
+	}
+
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		CgroupScanner: cgroup.NewScanner(tmpDir),
+		Metrics:       m,
+	})
+
+	candidates, err := c.scanCgroupsForSwap(context.Background())
+	if err != nil {
+		t.Fatalf("scanCgroupsForSwap() error = %v", err)
+	}
+	if len(candidates) != 0 {
+		t.Errorf("scanCgroupsForSwap() returned %d candidates, want 0", len(candidates))
+	}
+	if got := testutil.ToFloat64(m.ScanReadErrors.WithLabelValues("memory.pressure")); got != 2 {
+		t.Errorf("ScanReadErrors{memory.pressure} = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.ScanReadErrors.WithLabelValues("memory.max")); got != 1 {
+		t.Errorf("ScanReadErrors{memory.max} = %v, want 1", got)
+	}
+
+	// /candidates requests rescan the same cgroups; only reconcile scans count
+	if _, err := c.ListCandidates(context.Background()); err != nil {
+		t.Fatalf("ListCandidates() error = %v", err)
+	}
+	if got := testutil.ToFloat64(m.ScanReadErrors.WithLabelValues("memory.pressure")); got != 2 {
+		t.Errorf("ScanReadErrors{memory.pressure} after ListCandidates = %v, want still 2", got)
+	}
+}
@@ -0,0 +1,62 @@
+package controller
+
+// Decision actions reported to a DecisionSink
+const (
+	// DecisionBelowThreshold means the pod uses swap but triggered nothing
+	DecisionBelowThreshold = "below_threshold"
+	// DecisionSkipped means the pod was over threshold but spared; Reason is
+	// the pods_skipped_total reason
+	DecisionSkipped = "skipped"
+	// DecisionDryRun means the pod would have been killed; Reason says why it wasn't
+	DecisionDryRun = "dry_run"
+	// DecisionKilled means the kill action succeeded
+	DecisionKilled = "killed"
+	// DecisionKillFailed means the kill action failed; Reason is the error
+	DecisionKillFailed = "kill_failed"
+)
+
+// Reasons on DecisionDryRun decisions
+const (
+	dryRunReasonGlobal    = "dry_run"
+	dryRunReasonNamespace = "namespace_dry_run"
+	dryRunReasonStartup   = "startup_grace"
+	dryRunReasonPaused    = "paused"
+)
+
+// Decision is the outcome for one pod using swap in one reconcile
+type Decision struct {
+	UID         string
+	Namespace   string // empty if the pod is not in the informer cache
+	Name        string
+	SwapPercent float64
+	Action      string // one of the Decision* constants
+	Reason      string // skip reason, dry-run reason or error; empty otherwise
+}
+
+// DecisionSink receives every Decision made by the reconcile loop. It is
+// called synchronously from the loop, so implementations must be fast.
+type DecisionSink interface {
+	Record(Decision)
+}
+
+// recordDecision reports a decision to the configured sink, if any
+func (c *Controller) recordDecision(cand PodCandidate, action, reason string) {
+	if c.config.DecisionSink == nil {
+		return
+	}
+	// Pods below threshold are never resolved by the loop itself
+	if cand.Name == "" && c.config.PodInformer != nil {
+		if pod := c.config.PodInformer.GetPodByUID(cand.UID); pod != nil {
+			cand.Namespace = pod.Namespace
+			cand.Name = pod.Name
+		}
+	}
+	c.config.DecisionSink.Record(Decision{
+		UID:         cand.UID,
+		Namespace:   cand.Namespace,
+		Name:        cand.Name,
+		SwapPercent: cand.SwapPercent,
+		Action:      action,
+		Reason:      reason,
+	})
+}
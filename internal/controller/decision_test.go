@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/rophy/kube-soomkiller/internal/cgroup"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// decisionRecorder collects decisions for assertions
+type decisionRecorder struct {
+	decisions []Decision
+}
+
+func (r *decisionRecorder) Record(d Decision) {
+	r.decisions = append(r.decisions, d)
+}
+
+func TestFindAndKillOverThreshold_Decisions(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	pods := []*corev1.Pod{
+		createPodWithUID("heavy", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		createPodWithUID("light", "default", "test-node", "bbbb1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		createPodWithUID("system", "kube-system", "test-node", "cccc1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+	}
+	// heavy and system swap 100MB of 512MB; light swaps 1MB of 512MB
+	swap := []int64{100 << 20, 1 << 20, 100 << 20}
+	for i, pod := range pods {
+		uid := strings.ReplaceAll(string(pod.UID), "-", "_")
+		createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+uid+".slice/cri-containerd-"+uid[:4]+".scope", swap[i], 512<<20)
+	}
+
+	recorder := &decisionRecorder{}
+	c := New(Config{
+		SwapThresholdPercent: 1.0,
+		ProtectedNamespaces:  []string{"kube-system"},
+		K8sClient:            fake.NewSimpleClientset(pods[0], pods[1], pods[2]),
+		CgroupScanner:        cgroup.NewScanner(tmpDir),
+		PodInformer:          newTestPodInformer(t, pods...),
+		DecisionSink:         recorder,
+	})
+
+	if err := c.findAndKillOverThreshold(context.Background()); err != nil {
+		t.Fatalf("findAndKillOverThreshold() error = %v", err)
+	}
+
+	var got []string
+	for _, d := range recorder.decisions {
+		got = append(got, d.Namespace+"/"+d.Name+" "+d.Action+" "+d.Reason)
+	}
+	sort.Strings(got)
+	want := []string{
+		"default/heavy killed ",
+		"default/light below_threshold ",
+		"kube-system/system skipped protected_namespace",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decisions = %q, want %q", got, want)
+	}
+}
+
+func TestTerminatePod_DryRunDecision(t *testing.T) {
+	recorder := &decisionRecorder{}
+	c := New(Config{
+		DryRun:       true,
+		PodInformer:  newTestPodInformer(t),
+		DecisionSink: recorder,
+	})
+
+	cand := PodCandidate{UID: "pod-uid-123", Namespace: "default", Name: "test-pod", SwapPercent: 12.5}
+	if _, err := c.terminatePod(context.Background(), cand); err != nil {
+		t.Fatalf("terminatePod() error = %v", err)
+	}
+
+	want := []Decision{{UID: "pod-uid-123", Namespace: "default", Name: "test-pod", SwapPercent: 12.5, Action: DecisionDryRun, Reason: dryRunReasonGlobal}}
+	if !reflect.DeepEqual(recorder.decisions, want) {
+		t.Errorf("decisions = %+v, want %+v", recorder.decisions, want)
+	}
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// podDeleted is the PodInformer delete handler. It confirms tracked kills
+// and queues the pod's per-UID state to be dropped by the next reconcile.
+// It runs on the informer's goroutine.
+func (c *Controller) podDeleted(apiUID string) {
+	// Per-UID state is keyed by the UID as parsed from the cgroup path
+	uid := strings.ReplaceAll(apiUID, "_", "-")
+
+	c.deletedMu.Lock()
+	killedAt, killed := c.pendingKills[uid]
+	delete(c.pendingKills, uid)
+	c.deletedUIDs = append(c.deletedUIDs, uid)
+	c.deletedMu.Unlock()
+
+	if killed {
+		lag := time.Since(killedAt)
+		klog.V(2).InfoS("Killed pod removed", "uid", uid, "lag", lag)
+		if c.config.Metrics != nil {
+			c.config.Metrics.KillToRemoval.Observe(lag.Seconds())
+		}
+	}
+}
+
+// trackKill records a kill so its pod's removal can be confirmed
+func (c *Controller) trackKill(uid string, at time.Time) {
+	if c.config.PodInformer == nil {
+		return
+	}
+	c.deletedMu.Lock()
+	defer c.deletedMu.Unlock()
+	c.pendingKills[uid] = at
+}
+
+// untrackKill forgets a kill whose delete call failed
+func (c *Controller) untrackKill(uid string) {
+	c.deletedMu.Lock()
+	defer c.deletedMu.Unlock()
+	delete(c.pendingKills, uid)
+}
+
+// forgetDeletedPods drops swap history, warning, escalation and would-kill
+// state, and their metric series, for pods deleted since the last reconcile, rather
+// than waiting for them to be pruned as absent from a scan
+func (c *Controller) forgetDeletedPods() {
+	c.deletedMu.Lock()
+	deleted := c.deletedUIDs
+	c.deletedUIDs = nil
+	c.deletedMu.Unlock()
+
+	for _, uid := range deleted {
+		if prev, ok := c.swapHistory[uid]; ok {
+			if c.config.Metrics != nil && prev.name != "" {
+				c.config.Metrics.PodSwapGrowthRate.DeleteLabelValues(prev.namespace, prev.name)
+				c.config.Metrics.PodSwapPercent.DeleteLabelValues(prev.namespace, prev.name)
+				c.config.Metrics.PodSwapNodeFrac.DeleteLabelValues(prev.namespace, prev.name)
+				c.config.Metrics.PodSwapOfLimit.DeleteLabelValues(prev.namespace, prev.name)
+			}
+			delete(c.swapHistory, uid)
+		}
+		if state, ok := c.warnings[uid]; ok {
+			if c.config.Metrics != nil && state.name != "" {
+				c.config.Metrics.PodsWarning.DeleteLabelValues(state.namespace, state.name)
+			}
+			delete(c.warnings, uid)
+		}
+		delete(c.overSince, uid)
+		delete(c.wouldKill, uid)
+	}
+}
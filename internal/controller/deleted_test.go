@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rophy/kube-soomkiller/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestForgetDeletedPods(t *testing.T) {
+	pod := createPodWithUID("web", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable)
+	informer := newTestPodInformer(t, pod)
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		PodInformer: informer,
+		Metrics:     m,
+	})
+
+	deletedUID := "aaaa1111-2222-3333-4444-555566667777"
+	now := time.Now()
+	c.swapHistory = map[string]swapSample{
+		deletedUID: {namespace: "default", name: "web", at: now},
+		"other":    {at: now},
+	}
+	c.warnings[deletedUID] = warnState{lastEvent: now, namespace: "default", name: "web"}
+	c.overSince[deletedUID] = now
+	m.PodSwapPercent.WithLabelValues("default", "web").Set(10)
+	m.PodsWarning.WithLabelValues("default", "web").Set(10)
+
+	// The handler runs on the informer's goroutine; state is only dropped by the next reconcile
+	informer.podDeleted("aaaa1111_2222_3333_4444_555566667777")
+	if _, ok := c.overSince[deletedUID]; !ok {
+		t.Fatal("overSince dropped before reconcile")
+	}
+
+	c.forgetDeletedPods()
+
+	if _, ok := c.swapHistory[deletedUID]; ok {
+		t.Error("swapHistory still has the deleted pod")
+	}
+	if _, ok := c.swapHistory["other"]; !ok {
+		t.Error("swapHistory dropped a pod that wasn't deleted")
+	}
+	if len(c.warnings) != 0 || len(c.overSince) != 0 {
+		t.Errorf("warnings = %v, overSince = %v, want both empty", c.warnings, c.overSince)
+	}
+	if got := testutil.CollectAndCount(m.PodSwapPercent); got != 0 {
+		t.Errorf("PodSwapPercent collected %d series, want 0", got)
+	}
+	if got := testutil.CollectAndCount(m.PodsWarning); got != 0 {
+		t.Errorf("PodsWarning collected %d series, want 0", got)
+	}
+}
@@ -0,0 +1,46 @@
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// emergencyGracePeriod returns the grace period in seconds to cap kills at
+// while node swap usage is above EmergencySwapPercent, or nil when the node
+// isn't under acute pressure (pods keep their own grace period)
+func (c *Controller) emergencyGracePeriod() *int64 {
+	if c.config.EmergencySwapPercent <= 0 {
+		return nil
+	}
+
+	percent, err := c.nodeSwapPercent()
+	if err != nil {
+		klog.ErrorS(err, "Failed to read node swap usage for emergency grace period")
+		return nil
+	}
+	if percent <= c.config.EmergencySwapPercent {
+		return nil
+	}
+
+	seconds := int64(c.config.EmergencyGracePeriod.Seconds())
+	klog.V(2).InfoS("Node under acute swap pressure, capping grace periods", "nodeSwapPercent", percent, "emergencySwapPercent", c.config.EmergencySwapPercent, "gracePeriodSeconds", seconds)
+	return &seconds
+}
+
+// gracePeriodOverride returns emergency if the pod's own grace period is
+// longer, and nil otherwise. Pods that already terminate faster (including
+// terminationGracePeriodSeconds: 0) are never slowed down.
+func (c *Controller) gracePeriodOverride(uid string, emergency *int64) *int64 {
+	if emergency == nil {
+		return nil
+	}
+
+	own := int64(corev1.DefaultTerminationGracePeriodSeconds)
+	if pod := c.config.PodInformer.GetPodByUID(uid); pod != nil && pod.Spec.TerminationGracePeriodSeconds != nil {
+		own = *pod.Spec.TerminationGracePeriodSeconds
+	}
+	if own <= *emergency {
+		return nil
+	}
+	return emergency
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rophy/kube-soomkiller/internal/cgroup"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newSwapsScanner returns a scanner whose /proc/swaps reports usedPercent of a single device
+func newSwapsScanner(t *testing.T, usedPercent int) *cgroup.Scanner {
+	t.Helper()
+	procDir := t.TempDir()
+	swaps := fmt.Sprintf("Filename\tType\tSize\tUsed\tPriority\n/dev/vdb\tpartition\t1000000\t%d\t-2\n", usedPercent*10000)
+	if err := os.WriteFile(filepath.Join(procDir, "swaps"), []byte(swaps), 0644); err != nil {
+		t.Fatalf("Failed to write swaps: %v", err)
+	}
+	return cgroup.NewScannerWithOptions(t.TempDir(), cgroup.Options{ProcPath: procDir})
+}
+
+func TestEmergencyGracePeriod(t *testing.T) {
+	tests := []struct {
+		name        string
+		threshold   float64
+		usedPercent int
+		want        *int64
+	}{
+		{"disabled", 0, 99, nil},
+		{"below high-water mark", 90, 50, nil},
+		{"above high-water mark", 90, 95, int64Ptr(5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(Config{
+				EmergencySwapPercent: tt.threshold,
+				EmergencyGracePeriod: 5 * time.Second,
+				CgroupScanner:        newSwapsScanner(t, tt.usedPercent),
+			})
+			got := c.emergencyGracePeriod()
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("emergencyGracePeriod() = %v, want %v", derefInt64(got), derefInt64(tt.want))
+			}
+		})
+	}
+}
+
+func TestGracePeriodOverride(t *testing.T) {
+	withGrace := func(uid string, seconds *int64) *corev1.Pod {
+		pod := createPodWithUID(uid, "default", "test-node", types.UID("uid-"+uid), corev1.PodQOSBurstable)
+		pod.Spec.TerminationGracePeriodSeconds = seconds
+		return pod
+	}
+	c := New(Config{
+		PodInformer: newTestPodInformer(t,
+			withGrace("db", int64Ptr(300)),
+			withGrace("instant", int64Ptr(0)),
+			withGrace("default", nil),
+		),
+	})
+	emergency := int64Ptr(5)
+
+	if got := c.gracePeriodOverride("uid-db", emergency); got == nil || *got != 5 {
+		t.Errorf("gracePeriodOverride(db, 300s) = %v, want 5", derefInt64(got))
+	}
+	if got := c.gracePeriodOverride("uid-default", emergency); got == nil || *got != 5 {
+		t.Errorf("gracePeriodOverride(default 30s) = %v, want 5", derefInt64(got))
+	}
+	if got := c.gracePeriodOverride("uid-instant", emergency); got != nil {
+		t.Errorf("gracePeriodOverride(instant, 0s) = %v, want nil", *got)
+	}
+	if got := c.gracePeriodOverride("uid-db", nil); got != nil {
+		t.Errorf("gracePeriodOverride() without emergency = %v, want nil", *got)
+	}
+}
+
+func TestDeleteAction_GracePeriod(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		createPodWithUID("test-pod", "default", "test-node", "pod-uid-123", corev1.PodQOSBurstable),
+	)
+
+	action := &DeleteAction{Client: fakeClient}
+	if err := action.Execute(context.Background(), PodCandidate{Namespace: "default", Name: "test-pod", GracePeriodSeconds: int64Ptr(5)}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	for _, a := range fakeClient.Actions() {
+		if del, ok := a.(k8stesting.DeleteAction); ok {
+			if grace := del.GetDeleteOptions().GracePeriodSeconds; grace == nil || *grace != 5 {
+				t.Errorf("DeleteOptions.GracePeriodSeconds = %v, want 5", derefInt64(grace))
+			}
+			return
+		}
+	}
+	t.Error("Execute() issued no delete")
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+// derefInt64 formats an optional int64 for test messages
+func derefInt64(v *int64) string {
+	if v == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("%d", *v)
+}
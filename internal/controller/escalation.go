@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// pruneEscalations forgets pods that are no longer over threshold, so a pod
+// that recovers and later crosses again starts a fresh escalation delay
+func (c *Controller) pruneEscalations(overThreshold []PodCandidate) {
+	if len(c.overSince) == 0 {
+		return
+	}
+
+	over := make(map[string]bool, len(overThreshold))
+	for _, cand := range overThreshold {
+		over[cand.UID] = true
+	}
+	for uid := range c.overSince {
+		if !over[uid] {
+			delete(c.overSince, uid)
+		}
+	}
+}
+
+// escalationDue reports whether a resolved candidate has been over threshold
+// for at least EscalationDelay. The first time a pod is seen over threshold
+// it is recorded and a SoomkillEscalation warning event is emitted instead.
+// The delay is wall-clock based, so it does not depend on the poll interval.
+func (c *Controller) escalationDue(cand PodCandidate, now time.Time) bool {
+	if c.config.EscalationDelay <= 0 {
+		return true
+	}
+
+	since, ok := c.overSince[cand.UID]
+	if !ok {
+		c.overSince[cand.UID] = now
+		klog.InfoS("Pod over threshold, deferring kill for escalation delay", "pod", klog.KRef(cand.Namespace, cand.Name), "swapPercent", cand.SwapPercent, "escalationDelay", c.config.EscalationDelay)
+		if c.config.EventRecorder != nil {
+			if pod := c.config.PodInformer.GetPodByUID(cand.UID); pod != nil {
+				c.config.EventRecorder.Eventf(pod, corev1.EventTypeWarning, "SoomkillEscalation",
+					"Pod %s swap usage %.1f%% is over the kill threshold on node %s; it will be deleted if still over threshold in %s",
+					pod.Name, cand.SwapPercent, c.config.NodeName, c.config.EscalationDelay)
+			}
+		}
+		return false
+	}
+
+	return now.Sub(since) >= c.config.EscalationDelay
+}
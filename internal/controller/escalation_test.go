@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestEscalationDue(t *testing.T) {
+	uid := "aaaa1111-2222-3333-4444-555566667777"
+	pod := createPodWithUID("slow", "default", "test-node", types.UID(uid), corev1.PodQOSBurstable)
+
+	recorder := record.NewFakeRecorder(10)
+	c := New(Config{
+		EscalationDelay: time.Minute,
+		EventRecorder:   recorder,
+		PodInformer:     newTestPodInformer(t, pod),
+	})
+
+	now := time.Now()
+	cand := PodCandidate{UID: uid, Namespace: "default", Name: "slow", SwapPercent: 12.0}
+
+	if c.escalationDue(cand, now) {
+		t.Error("escalationDue() on first sight = true, want false")
+	}
+	if event := <-recorder.Events; !strings.Contains(event, "SoomkillEscalation") {
+		t.Errorf("event = %q, want SoomkillEscalation", event)
+	}
+
+	// Still within the delay, regardless of how many polls happened
+	if c.escalationDue(cand, now.Add(59*time.Second)) {
+		t.Error("escalationDue() within delay = true, want false")
+	}
+	if got := len(recorder.Events); got != 0 {
+		t.Errorf("events within delay = %d, want 0", got)
+	}
+
+	if !c.escalationDue(cand, now.Add(time.Minute)) {
+		t.Error("escalationDue() after delay = false, want true")
+	}
+
+	// Dropping below threshold resets the timer
+	c.pruneEscalations(nil)
+	if c.escalationDue(cand, now.Add(2*time.Minute)) {
+		t.Error("escalationDue() after recovery = true, want false")
+	}
+}
+
+func TestEscalationDue_Disabled(t *testing.T) {
+	c := New(Config{})
+	if !c.escalationDue(PodCandidate{UID: "pod-a"}, time.Now()) {
+		t.Error("escalationDue() with no delay = false, want true")
+	}
+	if len(c.overSince) != 0 {
+		t.Errorf("overSince = %v, want empty", c.overSince)
+	}
+}
@@ -1,10 +1,18 @@
 package controller
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
@@ -14,6 +22,13 @@ import (
 type PodInformer struct {
 	informer cache.SharedIndexInformer
 	indexer  cache.Indexer
+
+	// Unix nanoseconds of the last periodic resync (0 until the first one)
+	lastResync atomic.Int64
+
+	// Callbacks registered with OnPodDeleted
+	handlersMu     sync.Mutex
+	deleteHandlers []func(uid string)
 }
 
 const (
@@ -22,13 +37,23 @@ const (
 )
 
 // NewPodInformer creates an informer that watches only pods on the specified node.
-func NewPodInformer(client kubernetes.Interface, nodeName string, resyncPeriod time.Duration) *PodInformer {
-	listWatcher := cache.NewListWatchFromClient(
-		client.CoreV1().RESTClient(),
-		"pods",
-		corev1.NamespaceAll,
-		fields.OneTermEqualSelector("spec.nodeName", nodeName),
-	)
+// With runningOnly, pods in other phases are left out of the cache; see podFieldSelector.
+func NewPodInformer(client kubernetes.Interface, nodeName string, resyncPeriod time.Duration, runningOnly bool) *PodInformer {
+	// Typed list/watch calls rather than the raw REST client, so the
+	// informer also runs against a fake clientset in tests (which opts out
+	// of watch-list streaming, hence the semantics wrapper)
+	selector := podFieldSelector(nodeName, runningOnly).String()
+	listWatch := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = selector
+			return client.CoreV1().Pods(corev1.NamespaceAll).List(ctx, options)
+		},
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+			return client.CoreV1().Pods(corev1.NamespaceAll).Watch(ctx, options)
+		},
+	}
+	listWatcher := cache.ToListWatcherWithWatchListSemantics(listWatch, client)
 
 	informer := cache.NewSharedIndexInformer(
 		listWatcher,
@@ -40,10 +65,78 @@ func NewPodInformer(client kubernetes.Interface, nodeName string, resyncPeriod t
 		},
 	)
 
-	return &PodInformer{
+	p := &PodInformer{
 		informer: informer,
 		indexer:  informer.GetIndexer(),
 	}
+
+	// A resync redelivers cached objects unchanged, so an update with the
+	// same resourceVersion marks one
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldPod, okOld := oldObj.(*corev1.Pod)
+			newPod, okNew := newObj.(*corev1.Pod)
+			if okOld && okNew && oldPod.ResourceVersion == newPod.ResourceVersion {
+				p.lastResync.Store(time.Now().UnixNano())
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if pod, ok := obj.(*corev1.Pod); ok {
+				p.podDeleted(string(pod.UID))
+			}
+		},
+	})
+
+	return p
+}
+
+// podFieldSelector selects the pods on nodeName, and with runningOnly only
+// those in phase Running. Completed pods can't swap, but neither can the
+// cache see Pending pods, whose init containers may already be running; a
+// pod leaving Running arrives as a delete.
+func podFieldSelector(nodeName string, runningOnly bool) fields.Selector {
+	selector := fields.OneTermEqualSelector("spec.nodeName", nodeName)
+	if runningOnly {
+		selector = fields.AndSelectors(selector, fields.OneTermEqualSelector("status.phase", string(corev1.PodRunning)))
+	}
+	return selector
+}
+
+// nodeSelectorCheckLimit bounds the pods listed by VerifyNodeSelector; one
+// page is enough to tell whether the selector is applied
+const nodeSelectorCheckLimit = 500
+
+// VerifyNodeSelector lists pods with the same spec.nodeName field selector as
+// the informer and returns an error if any returned pod is on another node.
+// Some API servers and proxies silently drop field selectors, which would
+// make the informer cluster-wide and let kills target pods on other nodes.
+func VerifyNodeSelector(ctx context.Context, client kubernetes.Interface, nodeName string) error {
+	pods, err := client.CoreV1().Pods(corev1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+		Limit:         nodeSelectorCheckLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pods on node %s: %w", nodeName, err)
+	}
+
+	var foreign []string
+	for i := range pods.Items {
+		if pod := &pods.Items[i]; pod.Spec.NodeName != nodeName {
+			foreign = append(foreign, pod.Namespace+"/"+pod.Name+" (node "+pod.Spec.NodeName+")")
+		}
+	}
+	if len(foreign) > 0 {
+		examples := foreign
+		if len(examples) > 3 {
+			examples = examples[:3]
+		}
+		return fmt.Errorf("spec.nodeName field selector not honored: %d of %d listed pods are on other nodes, e.g. %s",
+			len(foreign), len(pods.Items), strings.Join(examples, ", "))
+	}
+	return nil
 }
 
 // uidIndexFunc indexes pods by their UID
@@ -67,7 +160,25 @@ func (p *PodInformer) WaitForCacheSync(stopCh <-chan struct{}) bool {
 }
 
 // GetPodByUID returns the pod with the given UID, or nil if not found.
+// UIDs extracted from cgroup paths have underscores converted to dashes; if
+// that form misses, the raw underscore form is tried as well so pods whose
+// UID isn't a standard UUID are not silently dropped.
 func (p *PodInformer) GetPodByUID(uid string) *corev1.Pod {
+	if pod := p.getPodByIndexedUID(uid); pod != nil {
+		return pod
+	}
+
+	if rawUID := strings.ReplaceAll(uid, "-", "_"); rawUID != uid {
+		if pod := p.getPodByIndexedUID(rawUID); pod != nil {
+			klog.V(4).InfoS("Found pod by raw cgroup UID", "uid", uid, "rawUID", rawUID, "pod", klog.KObj(pod))
+			return pod
+		}
+	}
+
+	return nil
+}
+
+func (p *PodInformer) getPodByIndexedUID(uid string) *corev1.Pod {
 	objs, err := p.indexer.ByIndex(uidIndex, uid)
 	if err != nil {
 		klog.InfoS("Failed to look up pod by UID", "uid", uid, "err", err)
@@ -85,6 +196,19 @@ func (p *PodInformer) GetPodByUID(uid string) *corev1.Pod {
 	return pod
 }
 
+// GetPod returns the pod with the given namespace and name, or nil if not found.
+func (p *PodInformer) GetPod(namespace, name string) *corev1.Pod {
+	obj, exists, err := p.indexer.GetByKey(namespace + "/" + name)
+	if err != nil || !exists {
+		return nil
+	}
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil
+	}
+	return pod
+}
+
 // ListPods returns all pods currently in the cache.
 func (p *PodInformer) ListPods() []*corev1.Pod {
 	objs := p.indexer.List()
@@ -96,3 +220,33 @@ func (p *PodInformer) ListPods() []*corev1.Pod {
 	}
 	return pods
 }
+
+// OnPodDeleted registers fn to be called with the API UID of every pod
+// removed from the cache. fn runs on the informer's goroutine and must not
+// block. Register before Run.
+func (p *PodInformer) OnPodDeleted(fn func(uid string)) {
+	p.handlersMu.Lock()
+	defer p.handlersMu.Unlock()
+	p.deleteHandlers = append(p.deleteHandlers, fn)
+}
+
+// podDeleted calls the OnPodDeleted handlers for a pod leaving the cache
+func (p *PodInformer) podDeleted(uid string) {
+	p.handlersMu.Lock()
+	handlers := p.deleteHandlers
+	p.handlersMu.Unlock()
+
+	for _, fn := range handlers {
+		fn(uid)
+	}
+}
+
+// LastResync returns when the informer last resynced, or the zero time if it
+// hasn't yet
+func (p *PodInformer) LastResync() time.Time {
+	nanos := p.lastResync.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
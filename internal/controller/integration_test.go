@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rophy/kube-soomkiller/internal/cgroup"
+	"github.com/rophy/kube-soomkiller/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+)
+
+// filterPodsByNode makes the fake clientset honor the informer's pod field
+// selector on lists, as the API server does. The fake ignores it otherwise.
+func filterPodsByNode(t *testing.T, client *fake.Clientset) {
+	t.Helper()
+	client.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		obj, err := client.Tracker().List(corev1.SchemeGroupVersion.WithResource("pods"), corev1.SchemeGroupVersion.WithKind("Pod"), action.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+		list := obj.(*corev1.PodList)
+		selector := action.(k8stesting.ListAction).GetListRestrictions().Fields
+
+		filtered := &corev1.PodList{ListMeta: list.ListMeta}
+		for _, pod := range list.Items {
+			if selector.Matches(fields.Set{"spec.nodeName": pod.Spec.NodeName, "status.phase": string(pod.Status.Phase)}) {
+				filtered.Items = append(filtered.Items, pod)
+			}
+		}
+		return true, filtered, nil
+	})
+}
+
+// TestRun_KillsOverThresholdPod drives Controller.Run end to end: a real
+// PodInformer on a fake clientset, a fake cgroup tree, and the full
+// reconcile path through the delete, the kill event and the informer's
+// delete handler.
+func TestRun_KillsOverThresholdPod(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	overUID := "aaaa1111_2222_3333_4444_555566667777"
+	underUID := "bbbb1111_2222_3333_4444_555566667777"
+	// 100MB / 512MB = ~19.5% (over threshold)
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+overUID+".slice/cri-containerd-abc.scope", 100<<20, 512<<20)
+	// 1MB / 512MB = ~0.2% (under threshold)
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+underUID+".slice/cri-containerd-def.scope", 1<<20, 512<<20)
+
+	fakeClient := fake.NewSimpleClientset(
+		createPodWithUID("swapper", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		createPodWithUID("steady", "default", "test-node", "bbbb1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		createPodWithUID("elsewhere", "default", "other-node", "cccc1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+	)
+	filterPodsByNode(t, fakeClient)
+
+	informer := NewPodInformer(fakeClient, "test-node", 0, false)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go informer.Run(stopCh)
+	if !informer.WaitForCacheSync(stopCh) {
+		t.Fatal("informer cache did not sync")
+	}
+	if got := len(informer.ListPods()); got != 2 {
+		t.Fatalf("informer cached %d pods, want the 2 on test-node", got)
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	c := New(Config{
+		NodeName:             "test-node",
+		PollInterval:         10 * time.Millisecond,
+		SwapThresholdPercent: 1.0,
+		K8sClient:            fakeClient,
+		CgroupScanner:        cgroup.NewScannerWithOptions(tmpDir, cgroup.Options{ProcPath: tmpDir}),
+		PodInformer:          informer,
+		EventRecorder:        recorder,
+		Metrics:              metrics.NewMetrics("test-node"),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runDone := make(chan error, 1)
+	go func() { runDone <- c.Run(ctx) }()
+	defer func() {
+		cancel()
+		if err := <-runDone; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	}()
+
+	// The kill is confirmed once the informer's delete handler sees the pod go
+	deadline := time.After(5 * time.Second)
+	for {
+		_, err := fakeClient.CoreV1().Pods("default").Get(context.Background(), "swapper", metav1.GetOptions{})
+		c.deletedMu.Lock()
+		pending := len(c.pendingKills)
+		c.deletedMu.Unlock()
+		if apierrors.IsNotFound(err) && informer.GetPodByUID("aaaa1111-2222-3333-4444-555566667777") == nil && pending == 0 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("over-threshold pod not killed and removed from the cache in time (get err = %v, pending kills = %d)", err, pending)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	if _, err := fakeClient.CoreV1().Pods("default").Get(context.Background(), "steady", metav1.GetOptions{}); err != nil {
+		t.Errorf("pod under threshold was deleted: %v", err)
+	}
+
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, DefaultEventReason) || !strings.Contains(event, "swapper") {
+			t.Errorf("event = %q, want a %s event for swapper", event, DefaultEventReason)
+		}
+	default:
+		t.Error("no kill event emitted")
+	}
+}
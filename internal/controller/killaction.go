@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Kill modes selecting a KillAction (--kill-mode)
+const (
+	// KillModeDelete deletes the pod, bypassing PodDisruptionBudgets
+	KillModeDelete = "delete"
+	// KillModeEvict uses the Eviction API, so PodDisruptionBudgets are honored
+	KillModeEvict = "evict"
+)
+
+// KillAction performs the kill step for a pod that passed every filter.
+// Dry-run, events, owner annotation and auditing are handled by the
+// controller around it, so implementations only act on the pod. A non-nil
+// PodCandidate.GracePeriodSeconds must be honored where the action allows,
+// and the kill should be conditional on PodCandidate.UID.
+type KillAction interface {
+	Execute(ctx context.Context, cand PodCandidate) error
+}
+
+// NewKillAction returns the KillAction for a kill mode
+func NewKillAction(mode string, client kubernetes.Interface) (KillAction, error) {
+	switch mode {
+	case KillModeDelete:
+		return &DeleteAction{Client: client}, nil
+	case KillModeEvict:
+		return &EvictAction{Client: client}, nil
+	default:
+		return nil, fmt.Errorf("unknown kill mode %q: expected %s or %s", mode, KillModeDelete, KillModeEvict)
+	}
+}
+
+// DeleteAction deletes the pod, letting Kubernetes handle graceful termination
+type DeleteAction struct {
+	Client kubernetes.Interface
+}
+
+// Execute implements KillAction
+func (a *DeleteAction) Execute(ctx context.Context, cand PodCandidate) error {
+	opts := deleteOptions(cand)
+	if err := a.Client.CoreV1().Pods(cand.Namespace).Delete(ctx, cand.Name, opts); err != nil {
+		return fmt.Errorf("failed to delete pod %s/%s: %w", cand.Namespace, cand.Name, err)
+	}
+	return nil
+}
+
+// EvictAction evicts the pod through the Eviction API. An eviction blocked
+// by a PodDisruptionBudget fails and is retried on the next reconcile.
+type EvictAction struct {
+	Client kubernetes.Interface
+}
+
+// Execute implements KillAction
+func (a *EvictAction) Execute(ctx context.Context, cand PodCandidate) error {
+	opts := deleteOptions(cand)
+	eviction := &policyv1.Eviction{
+		ObjectMeta:    metav1.ObjectMeta{Name: cand.Name, Namespace: cand.Namespace},
+		DeleteOptions: &opts,
+	}
+	if err := a.Client.PolicyV1().Evictions(cand.Namespace).Evict(ctx, eviction); err != nil {
+		return fmt.Errorf("failed to evict pod %s/%s: %w", cand.Namespace, cand.Name, err)
+	}
+	return nil
+}
+
+// deleteOptions builds the options for killing cand. The UID precondition
+// makes the API server reject the request if the name now belongs to a
+// recreated pod.
+func deleteOptions(cand PodCandidate) metav1.DeleteOptions {
+	opts := metav1.DeleteOptions{GracePeriodSeconds: cand.GracePeriodSeconds}
+	if cand.UID != "" {
+		opts.Preconditions = metav1.NewUIDPreconditions(cand.UID)
+	}
+	return opts
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestNewKillAction(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	if action, err := NewKillAction(KillModeDelete, client); err != nil {
+		t.Errorf("NewKillAction(delete) error = %v", err)
+	} else if _, ok := action.(*DeleteAction); !ok {
+		t.Errorf("NewKillAction(delete) = %T, want *DeleteAction", action)
+	}
+	if action, err := NewKillAction(KillModeEvict, client); err != nil {
+		t.Errorf("NewKillAction(evict) error = %v", err)
+	} else if _, ok := action.(*EvictAction); !ok {
+		t.Errorf("NewKillAction(evict) = %T, want *EvictAction", action)
+	}
+	if _, err := NewKillAction("sigkill", client); err == nil {
+		t.Error("NewKillAction(sigkill) expected error")
+	}
+}
+
+func TestEvictAction(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		createPodWithUID("test-pod", "default", "test-node", "pod-uid-123", corev1.PodQOSBurstable),
+	)
+
+	c := New(Config{
+		K8sClient:   fakeClient,
+		KillAction:  &EvictAction{Client: fakeClient},
+		PodInformer: newTestPodInformer(t),
+	})
+
+	if _, err := c.terminatePod(context.Background(), PodCandidate{Namespace: "default", Name: "test-pod"}); err != nil {
+		t.Fatalf("terminatePod() unexpected error: %v", err)
+	}
+
+	var evicted, deleted bool
+	for _, action := range fakeClient.Actions() {
+		switch {
+		case action.Matches("create", "pods") && action.GetSubresource() == "eviction":
+			evicted = action.(k8stesting.CreateAction).GetObject().(metav1.Object).GetName() == "test-pod"
+		case action.Matches("delete", "pods"):
+			deleted = true
+		}
+	}
+	if !evicted {
+		t.Error("terminatePod() did not create an eviction for test-pod")
+	}
+	if deleted {
+		t.Error("terminatePod() deleted the pod directly in evict mode")
+	}
+}
+
+func TestDeleteAction_UIDPrecondition(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		createPodWithUID("test-pod", "default", "test-node", "pod-uid-123", corev1.PodQOSBurstable),
+	)
+
+	action := &DeleteAction{Client: fakeClient}
+	if err := action.Execute(context.Background(), PodCandidate{UID: "pod-uid-123", Namespace: "default", Name: "test-pod"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	for _, a := range fakeClient.Actions() {
+		if del, ok := a.(k8stesting.DeleteAction); ok {
+			pre := del.GetDeleteOptions().Preconditions
+			if pre == nil || pre.UID == nil || *pre.UID != "pod-uid-123" {
+				t.Errorf("DeleteOptions.Preconditions = %+v, want UID pod-uid-123", pre)
+			}
+			return
+		}
+	}
+	t.Error("Execute() issued no delete")
+}
+
+func TestCurrentPodUID(t *testing.T) {
+	scanned := createPodWithUID("web-0", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable)
+	// A StatefulSet pod recreated under the same name gets a new UID
+	recreated := createPodWithUID("db-0", "default", "test-node", "bbbb1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable)
+
+	c := New(Config{PodInformer: newTestPodInformer(t, scanned, recreated)})
+
+	if uid, ok := c.currentPodUID(PodCandidate{UID: string(scanned.UID), Namespace: "default", Name: "web-0"}); !ok || uid != string(scanned.UID) {
+		t.Errorf("currentPodUID(web-0) = %q, %v, want %q, true", uid, ok, scanned.UID)
+	}
+	if _, ok := c.currentPodUID(PodCandidate{UID: "cccc1111-2222-3333-4444-555566667777", Namespace: "default", Name: "db-0"}); ok {
+		t.Error("currentPodUID() for a replaced pod = true, want false")
+	}
+	if _, ok := c.currentPodUID(PodCandidate{UID: string(scanned.UID), Namespace: "default", Name: "gone"}); ok {
+		t.Error("currentPodUID() for a deleted name = true, want false")
+	}
+}
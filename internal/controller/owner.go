@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+// LastKillAnnotation is set on a killed pod's ReplicaSet or StatefulSet when
+// AnnotateOwner is enabled, so the kill stays visible after the pod is gone
+const LastKillAnnotation = "soomkiller.rophy.dev/last-kill"
+
+// lastKill is the JSON value of LastKillAnnotation
+type lastKill struct {
+	Time        time.Time `json:"time"`
+	Node        string    `json:"node"`
+	Pod         string    `json:"pod"`
+	SwapPercent float64   `json:"swapPercent"`
+}
+
+// annotateOwner records the kill on the pod's controlling ReplicaSet or
+// StatefulSet. Other owner kinds are left alone. Failures are logged and
+// never block the kill.
+func (c *Controller) annotateOwner(ctx context.Context, cand PodCandidate, now time.Time) {
+	if !c.config.AnnotateOwner {
+		return
+	}
+
+	pod := c.config.PodInformer.GetPodByUID(cand.UID)
+	if pod == nil {
+		klog.V(3).InfoS("Could not get pod from cache for owner annotation", "pod", klog.KRef(cand.Namespace, cand.Name))
+		return
+	}
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return
+	}
+
+	patch, err := lastKillPatch(lastKill{
+		Time:        now.UTC().Truncate(time.Second),
+		Node:        c.config.NodeName,
+		Pod:         cand.Name,
+		SwapPercent: cand.SwapPercent,
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to build owner annotation patch", "pod", klog.KRef(cand.Namespace, cand.Name))
+		return
+	}
+
+	switch owner.Kind {
+	case "ReplicaSet":
+		_, err = c.config.K8sClient.AppsV1().ReplicaSets(pod.Namespace).Patch(ctx, owner.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	case "StatefulSet":
+		_, err = c.config.K8sClient.AppsV1().StatefulSets(pod.Namespace).Patch(ctx, owner.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	default:
+		return
+	}
+	if err != nil {
+		klog.ErrorS(err, "Failed to annotate pod owner", "pod", klog.KObj(pod), "ownerKind", owner.Kind, "owner", owner.Name)
+		return
+	}
+	klog.V(2).InfoS("Annotated pod owner", "pod", klog.KObj(pod), "ownerKind", owner.Kind, "owner", owner.Name)
+}
+
+// lastKillPatch builds a merge patch setting LastKillAnnotation
+func lastKillPatch(kill lastKill) ([]byte, error) {
+	value, err := json.Marshal(kill)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{LastKillAnnotation: string(value)},
+		},
+	})
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// PauseAnnotation on the controller's own Node pauses kills while its value
+// is true, e.g. during node maintenance. Scans, metrics and warnings continue.
+const PauseAnnotation = "soomkiller.rophy.dev/pause"
+
+// updatePaused re-reads PauseAnnotation from the node at most once per
+// PauseRefresh. A failed read keeps the last known state and is retried
+// after PauseRefresh too, so an API outage neither pauses nor resumes kills
+// nor blocks every reconcile on the GET.
+func (c *Controller) updatePaused(ctx context.Context, now time.Time) {
+	if c.config.PauseRefresh <= 0 || c.config.K8sClient == nil {
+		return
+	}
+	if !c.pauseCheckedAt.IsZero() && now.Sub(c.pauseCheckedAt) < c.config.PauseRefresh {
+		return
+	}
+
+	c.pauseCheckedAt = now
+
+	node, err := c.config.K8sClient.CoreV1().Nodes().Get(ctx, c.config.NodeName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Failed to read node for pause annotation", "node", c.config.NodeName, "paused", c.paused, "retryIn", c.config.PauseRefresh)
+		return
+	}
+
+	value := node.Annotations[PauseAnnotation]
+	paused, _ := strconv.ParseBool(value)
+	if paused != c.paused {
+		if paused {
+			klog.InfoS("Kills paused by node annotation", "node", c.config.NodeName, "annotation", PauseAnnotation)
+		} else {
+			klog.InfoS("Kills resumed, node pause annotation cleared", "node", c.config.NodeName, "annotation", PauseAnnotation)
+		}
+	}
+	c.paused = paused
+
+	if c.config.Metrics != nil {
+		if paused {
+			c.config.Metrics.KillsPaused.Set(1)
+		} else {
+			c.config.Metrics.KillsPaused.Set(0)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rophy/kube-soomkiller/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestTerminatePod_PausedByNodeAnnotation(t *testing.T) {
+	pod := createPodWithUID("web", "default", "test-node", "pod-uid-123", corev1.PodQOSBurstable)
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        "test-node",
+		Annotations: map[string]string{PauseAnnotation: "true"},
+	}}
+	fakeClient := fake.NewSimpleClientset(pod, node)
+	m := metrics.NewMetrics("test-node")
+	recorder := &decisionRecorder{}
+	c := New(Config{
+		NodeName:     "test-node",
+		PauseRefresh: time.Minute,
+		K8sClient:    fakeClient,
+		PodInformer:  newTestPodInformer(t, pod),
+		Metrics:      m,
+		DecisionSink: recorder,
+	})
+	cand := PodCandidate{UID: "pod-uid-123", Namespace: "default", Name: "web", SwapPercent: 20}
+	now := time.Now()
+
+	c.updatePaused(context.Background(), now)
+	if killed, err := c.terminatePod(context.Background(), cand); err != nil || killed {
+		t.Fatalf("terminatePod() = %v, %v, want not killed while paused", killed, err)
+	}
+	if _, err := fakeClient.CoreV1().Pods("default").Get(context.Background(), "web", metav1.GetOptions{}); err != nil {
+		t.Fatalf("pod deleted while paused: %v", err)
+	}
+	if len(recorder.decisions) != 1 || recorder.decisions[0].Action != DecisionDryRun || recorder.decisions[0].Reason != dryRunReasonPaused {
+		t.Errorf("decisions = %+v, want one dry_run/%s", recorder.decisions, dryRunReasonPaused)
+	}
+	if got := testutil.ToFloat64(m.KillsPaused); got != 1 {
+		t.Errorf("KillsPaused = %v, want 1", got)
+	}
+
+	// Clearing the annotation takes effect at the next refresh, not before
+	node.Annotations = nil
+	if _, err := fakeClient.CoreV1().Nodes().Update(context.Background(), node, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update node: %v", err)
+	}
+	c.updatePaused(context.Background(), now.Add(time.Second))
+	if !c.paused {
+		t.Error("paused = false before the refresh interval elapsed, want the cached true")
+	}
+	c.updatePaused(context.Background(), now.Add(time.Minute))
+	if c.paused {
+		t.Error("paused = true after the annotation was cleared, want false")
+	}
+	if got := testutil.ToFloat64(m.KillsPaused); got != 0 {
+		t.Errorf("KillsPaused = %v, want 0", got)
+	}
+
+	if killed, err := c.terminatePod(context.Background(), cand); err != nil || !killed {
+		t.Fatalf("terminatePod() = %v, %v, want killed after resuming", killed, err)
+	}
+	if _, err := fakeClient.CoreV1().Pods("default").Get(context.Background(), "web", metav1.GetOptions{}); err == nil {
+		t.Error("pod not deleted after kills resumed")
+	}
+}
+
+func TestUpdatePaused_KeepsStateOnError(t *testing.T) {
+	// No Node object: every read fails
+	fakeClient := fake.NewSimpleClientset()
+	var gets int
+	fakeClient.PrependReactor("get", "nodes", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		gets++
+		return false, nil, nil
+	})
+	c := New(Config{
+		NodeName:     "test-node",
+		PauseRefresh: time.Minute,
+		K8sClient:    fakeClient,
+	})
+	c.paused = true
+
+	now := time.Now()
+	c.updatePaused(context.Background(), now)
+	if !c.paused {
+		t.Error("paused = false after a failed node read, want the last known true")
+	}
+
+	// A failed read backs off like a successful one instead of blocking every reconcile
+	c.updatePaused(context.Background(), now.Add(time.Second))
+	if gets != 1 {
+		t.Errorf("node read %d times within the refresh interval after a failure, want 1", gets)
+	}
+	c.updatePaused(context.Background(), now.Add(time.Minute))
+	if gets != 2 {
+		t.Errorf("node read %d times after the refresh interval, want a retry", gets)
+	}
+}
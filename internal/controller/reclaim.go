@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// ReclaimCounters are the /proc/vmstat counters of pages the kernel freed by
+// reclaim, from kswapd and from allocating tasks, summed into the reclaim rate
+var ReclaimCounters = []string{"pgsteal_kswapd", "pgsteal_direct"}
+
+// DefaultReclaimThreshold is the reclaim rate, in pages/sec, above which
+// DeferOnActiveReclaim considers the kernel to be actively freeing memory
+const DefaultReclaimThreshold = 1000
+
+// sampleReclaim reads /proc/vmstat and returns the pages reclaimed per
+// second since the previous sample. ok is false when there is no previous
+// sample to compare against or the counters could not be read.
+func (c *Controller) sampleReclaim(now time.Time) (rate float64, ok bool) {
+	counters, err := c.config.CgroupScanner.GetVmstatCounters(ReclaimCounters)
+	if err != nil {
+		klog.V(2).InfoS("Failed to read reclaim stats", "err", err)
+		return 0, false
+	}
+
+	prev, prevAt := c.lastReclaim, c.lastReclaimAt
+	c.lastReclaim, c.lastReclaimAt = counters, now
+	if prev == nil {
+		return 0, false
+	}
+
+	elapsed := now.Sub(prevAt).Seconds()
+	if elapsed <= 0 {
+		return 0, false
+	}
+
+	for _, name := range ReclaimCounters {
+		rate += float64(counterDelta(prev[name], counters[name])) / elapsed
+	}
+	return rate, true
+}
+
+// updateReclaim samples the reclaim rate for DeferOnActiveReclaim and
+// records whether reclaim is active this pass. A pass with no previous
+// sample counts as inactive.
+func (c *Controller) updateReclaim(now time.Time) {
+	if !c.config.DeferOnActiveReclaim {
+		return
+	}
+	rate, ok := c.sampleReclaim(now)
+	if ok && c.config.Metrics != nil {
+		c.config.Metrics.ReclaimRate.Set(rate)
+	}
+
+	threshold := c.config.ReclaimThreshold
+	if threshold <= 0 {
+		threshold = DefaultReclaimThreshold
+	}
+	c.reclaimActive = ok && rate > threshold
+	if !c.reclaimActive {
+		// The next reclaim episode gets its own deferral
+		c.reclaimDeferred = false
+	}
+	if ok {
+		klog.V(4).InfoS("Sampled node reclaim rate", "pagesPerSec", rate, "active", c.reclaimActive)
+	}
+}
+
+// deferForReclaim reports whether this pass's kills should wait because
+// the kernel is actively reclaiming memory. Each reclaim episode defers
+// kills for one pass only, so sustained reclaim that isn't relieving the
+// pressure can't hold kills off indefinitely.
+func (c *Controller) deferForReclaim() bool {
+	if !c.reclaimActive || c.reclaimDeferred {
+		return false
+	}
+	c.reclaimDeferred = true
+	return true
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rophy/kube-soomkiller/internal/cgroup"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDeferOnActiveReclaim(t *testing.T) {
+	tmpDir := t.TempDir()
+	procDir := t.TempDir()
+
+	pod := createPodWithUID("swapper", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable)
+	// 100MB / 512MB = ~19.5% (over threshold)
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podaaaa1111_2222_3333_4444_555566667777.slice/cri-containerd-abc.scope", 100<<20, 512<<20)
+
+	writeReclaim := func(kswapd, direct uint64) {
+		t.Helper()
+		content := fmt.Sprintf("pswpin 0\npswpout 0\npgsteal_kswapd %d\npgsteal_direct %d\n", kswapd, direct)
+		if err := os.WriteFile(filepath.Join(procDir, "vmstat"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write vmstat: %v", err)
+		}
+	}
+
+	fakeClient := fake.NewSimpleClientset(pod)
+	recorder := &decisionRecorder{}
+	c := New(Config{
+		SwapThresholdPercent: 1.0,
+		DeferOnActiveReclaim: true,
+		ReclaimThreshold:     100,
+		K8sClient:            fakeClient,
+		CgroupScanner:        cgroup.NewScannerWithOptions(tmpDir, cgroup.Options{ProcPath: procDir}),
+		PodInformer:          newTestPodInformer(t, pod),
+		DecisionSink:         recorder,
+	})
+	now := time.Now()
+
+	pass := func(at time.Time) string {
+		t.Helper()
+		recorder.decisions = nil
+		c.updateReclaim(at)
+		if err := c.findAndKillOverThreshold(context.Background()); err != nil {
+			t.Fatalf("findAndKillOverThreshold() error = %v", err)
+		}
+		if len(recorder.decisions) != 1 {
+			t.Fatalf("decisions = %+v, want 1", recorder.decisions)
+		}
+		return recorder.decisions[0].Action + " " + recorder.decisions[0].Reason
+	}
+
+	// Without a previous sample reclaim is not known to be active
+	writeReclaim(0, 0)
+	c.updateReclaim(now)
+	if c.reclaimActive {
+		t.Fatal("reclaimActive = true on the first sample, want false")
+	}
+
+	// 150 pages/sec: the first pass of the episode is deferred
+	writeReclaim(100, 50)
+	if got := pass(now.Add(time.Second)); got != DecisionSkipped+" "+skipReasonActiveReclaim {
+		t.Errorf("first pass with active reclaim = %q, want skipped %s", got, skipReasonActiveReclaim)
+	}
+	if _, err := fakeClient.CoreV1().Pods("default").Get(context.Background(), "swapper", metav1.GetOptions{}); err != nil {
+		t.Fatalf("pod deleted while reclaim was active: %v", err)
+	}
+
+	// Reclaim still active: the episode's deferral is used up, so the pod is killed
+	writeReclaim(300, 100)
+	if got := pass(now.Add(2 * time.Second)); got != DecisionKilled+" " {
+		t.Errorf("second pass with active reclaim = %q, want killed", got)
+	}
+
+	// Reclaim subsiding ends the episode; the next one defers again
+	writeReclaim(300, 100)
+	c.updateReclaim(now.Add(3 * time.Second))
+	writeReclaim(600, 100)
+	c.updateReclaim(now.Add(4 * time.Second))
+	if !c.deferForReclaim() {
+		t.Error("deferForReclaim() = false for a new reclaim episode, want true")
+	}
+}
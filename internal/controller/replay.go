@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/rophy/kube-soomkiller/internal/cgroup"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ReplayNamespace is the namespace of the stand-in pods a Replayer creates for
+// pod UIDs missing from a snapshot's pods.json
+const ReplayNamespace = "replay"
+
+// Snapshot is one captured node state: a cgroup tree rooted at Dir (the
+// directory holding kubepods.slice), the node's vmstat, meminfo and swaps
+// under Dir/proc, and optionally a pods.json v1 PodList for pod metadata
+type Snapshot struct {
+	Dir string
+	At  time.Time
+}
+
+// LoadSnapshots returns the snapshot directories under dir, oldest first.
+// Each snapshot is a subdirectory named by its capture time, either RFC 3339
+// or Unix seconds; other entries are ignored.
+func LoadSnapshots(dir string) ([]Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+	}
+
+	var snapshots []Snapshot
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		at, ok := parseSnapshotTime(entry.Name())
+		if !ok {
+			continue
+		}
+		snapshots = append(snapshots, Snapshot{Dir: filepath.Join(dir, entry.Name()), At: at})
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no snapshots named by RFC 3339 or Unix time found in %s", dir)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].At.Before(snapshots[j].At)
+	})
+	return snapshots, nil
+}
+
+// parseSnapshotTime parses a snapshot directory name as RFC 3339 or Unix seconds
+func parseSnapshotTime(name string) (time.Time, bool) {
+	if at, err := time.Parse(time.RFC3339, name); err == nil {
+		return at, true
+	}
+	if secs, err := strconv.ParseInt(name, 10, 64); err == nil {
+		return time.Unix(secs, 0), true
+	}
+	return time.Time{}, false
+}
+
+// Replayer runs the reconcile decision logic against a sequence of snapshots
+// without a Kubernetes API, for tuning thresholds offline. Kills are always
+// dry-run. State that spans reconciles (swap growth, escalation delays)
+// carries over between steps, timed by the snapshot capture times.
+type Replayer struct {
+	ctrl    *Controller
+	sink    *replaySink
+	options cgroup.Options
+}
+
+// replaySink collects the decisions of a single step
+type replaySink struct {
+	decisions []Decision
+}
+
+// Record implements DecisionSink
+func (s *replaySink) Record(d Decision) {
+	s.decisions = append(s.decisions, d)
+}
+
+// NewReplayer creates a Replayer from the decision settings in config.
+// Anything that would reach the API or the host (clients, events, audit,
+// metrics, taints, owner annotations) is disabled. options configures the
+// per-snapshot scanner; its ProcPath is replaced by each snapshot's.
+func NewReplayer(config Config, options cgroup.Options) *Replayer {
+	sink := &replaySink{}
+
+	config.DryRun = true
+	config.K8sClient = nil
+	config.EventRecorder = nil
+	config.AuditLog = nil
+	config.Metrics = nil
+	config.TaintOnPressure = false
+	config.AnnotateOwner = false
+	config.ScanTimeout = 0
+	config.DecisionSink = sink
+
+	return &Replayer{
+		ctrl:    New(config),
+		sink:    sink,
+		options: options,
+	}
+}
+
+// Step replays one snapshot and returns the decisions made for it. Snapshots
+// must be stepped in capture order.
+func (r *Replayer) Step(ctx context.Context, snap Snapshot) ([]Decision, error) {
+	options := r.options
+	options.ProcPath = filepath.Join(snap.Dir, "proc")
+	scanner := cgroup.NewScannerWithOptions(snap.Dir, options)
+
+	informer, err := snapshotPodInformer(scanner, snap.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	r.ctrl.config.CgroupScanner = scanner
+	r.ctrl.config.PodInformer = informer
+	r.ctrl.now = func() time.Time { return snap.At }
+	r.sink.decisions = nil
+
+	if err := r.ctrl.findAndKillOverThreshold(ctx); err != nil {
+		return nil, err
+	}
+	return r.sink.decisions, nil
+}
+
+// snapshotPodInformer builds a static pod cache from the snapshot's pods.json,
+// adding a Running stand-in pod in ReplayNamespace for every pod UID found in
+// the cgroup tree but not in pods.json
+func snapshotPodInformer(scanner *cgroup.Scanner, dir string) (*PodInformer, error) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+		uidIndex:             uidIndexFunc,
+	})
+	known := make(map[string]bool)
+
+	data, err := os.ReadFile(filepath.Join(dir, "pods.json"))
+	switch {
+	case err == nil:
+		var list corev1.PodList
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, fmt.Errorf("failed to parse %s/pods.json: %w", dir, err)
+		}
+		for i := range list.Items {
+			pod := &list.Items[i]
+			if err := indexer.Add(pod); err != nil {
+				return nil, err
+			}
+			known[string(pod.UID)] = true
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("failed to read %s/pods.json: %w", dir, err)
+	}
+
+	cgroups, err := scanner.FindPodCgroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pod cgroups in %s: %w", dir, err)
+	}
+	for _, cgroupPath := range cgroups.Cgroups {
+		uid := cgroup.ExtractPodUID(cgroupPath)
+		if uid == "" || known[uid] {
+			continue
+		}
+		known[uid] = true
+		if err := indexer.Add(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ReplayNamespace, Name: uid, UID: types.UID(uid)},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, QOSClass: corev1.PodQOSBurstable},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &PodInformer{indexer: indexer}, nil
+}
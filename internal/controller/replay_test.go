@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rophy/kube-soomkiller/internal/cgroup"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestReplayer(t *testing.T) {
+	dir := t.TempDir()
+
+	overUID := "aaaa1111_2222_3333_4444_555566667777"
+	underUID := "bbbb1111_2222_3333_4444_555566667777"
+	// Written out of order, with a stray directory that isn't a snapshot
+	for _, name := range []string{"1010", "1000", "notes"} {
+		snap := filepath.Join(dir, name)
+		// 100MB / 512MB = ~19.5% (over threshold)
+		createFakeCgroup(t, snap, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+overUID+".slice/cri-containerd-abc.scope", 100<<20, 512<<20)
+		// 1MB / 512MB = ~0.2% (under threshold)
+		createFakeCgroup(t, snap, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"+underUID+".slice/cri-containerd-def.scope", 1<<20, 512<<20)
+
+		// Only the over-threshold pod has real metadata
+		pods := corev1.PodList{Items: []corev1.Pod{
+			*createPodWithUID("web", "prod", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		}}
+		data, err := json.Marshal(pods)
+		if err != nil {
+			t.Fatalf("Failed to marshal pods: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(snap, "pods.json"), data, 0644); err != nil {
+			t.Fatalf("Failed to write pods.json: %v", err)
+		}
+	}
+
+	snapshots, err := LoadSnapshots(dir)
+	if err != nil {
+		t.Fatalf("LoadSnapshots() error = %v", err)
+	}
+	if len(snapshots) != 2 || !snapshots[0].At.Equal(time.Unix(1000, 0)) || !snapshots[1].At.Equal(time.Unix(1010, 0)) {
+		t.Fatalf("LoadSnapshots() = %+v, want 1000 then 1010", snapshots)
+	}
+
+	r := NewReplayer(Config{
+		SwapThresholdPercent: 1.0,
+		EscalationDelay:      5 * time.Second,
+	}, cgroup.Options{})
+
+	// The escalation delay is timed by capture times, not wall-clock
+	want := []map[string]Decision{
+		{
+			"prod/web": {Action: DecisionSkipped, Reason: skipReasonEscalation},
+			ReplayNamespace + "/bbbb1111-2222-3333-4444-555566667777": {Action: DecisionBelowThreshold},
+		},
+		{
+			"prod/web": {Action: DecisionDryRun, Reason: dryRunReasonGlobal},
+			ReplayNamespace + "/bbbb1111-2222-3333-4444-555566667777": {Action: DecisionBelowThreshold},
+		},
+	}
+	for i, snap := range snapshots {
+		decisions, err := r.Step(context.Background(), snap)
+		if err != nil {
+			t.Fatalf("Step(%s) error = %v", snap.Dir, err)
+		}
+		if len(decisions) != len(want[i]) {
+			t.Fatalf("Step(%s) returned %d decisions, want %d: %+v", snap.Dir, len(decisions), len(want[i]), decisions)
+		}
+		for _, d := range decisions {
+			w, ok := want[i][d.Namespace+"/"+d.Name]
+			if !ok || d.Action != w.Action || d.Reason != w.Reason {
+				t.Errorf("Step(%s) decision %s/%s = %s/%s, want %s/%s", snap.Dir, d.Namespace, d.Name, d.Action, d.Reason, w.Action, w.Reason)
+			}
+		}
+	}
+}
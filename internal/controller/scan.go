@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rophy/kube-soomkiller/internal/cgroup"
+	"k8s.io/klog/v2"
+)
+
+// SwapScan is the result of a single ScanSwapUsage pass
+type SwapScan struct {
+	// Burstable pods using swap, one per pod UID with containers aggregated
+	Candidates []PodCandidate
+
+	// Pods using swap per QoS class (burstable, besteffort, guaranteed),
+	// counted before the burstable filter
+	SwappingByQoS map[string]int
+
+	// Container scopes matching no runtime prefix
+	Unrecognized int
+
+	// Swapping burstable pods (distinct UIDs) left out of Candidates by the
+	// maxCandidates cap
+	Dropped int
+
+	// Containers skipped because a required cgroup file couldn't be read, by
+	// file name ("unknown" for errors not tied to a file)
+	ReadErrors map[string]int
+}
+
+// ScanSwapUsage scans container cgroups for pods using swap. It only reads
+// the cgroup filesystem: no API calls, no metrics, no side effects. Only
+// burstable pods are returned, since only they get swap in LimitedSwap mode.
+// skip, if non-nil, excludes individual containers from aggregation.
+// maxCandidates, if positive, bounds the pods retained to the highest-swap
+// ones seen; the rest are counted in Dropped. The scan stops with ctx.Err()
+// once ctx is done.
+func ScanSwapUsage(ctx context.Context, scanner *cgroup.Scanner, skip func(uid, cgroupPath string) bool, maxCandidates int) (*SwapScan, error) {
+	// Find all container cgroups via filesystem walk
+	cgroupsResult, err := scanner.FindPodCgroupsContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pod cgroups: %w", err)
+	}
+
+	if len(cgroupsResult.Unrecognized) > 0 {
+		klog.V(4).InfoS("Unrecognized cgroup patterns", "count", len(cgroupsResult.Unrecognized), "examples", unrecognizedExamples(cgroupsResult.Unrecognized))
+	}
+
+	// Track processed pods by UID to avoid duplicates (multiple containers per
+	// pod), keeping at most maxCandidates of the worst-swapping ones
+	processedPods := newCandidateHeap()
+	// Pod UIDs dropped by the cap. A pod's containers can be dropped, re-enter
+	// and be dropped again, or re-enter and stay, so this is a set.
+	dropped := make(map[string]struct{})
+	readErrors := make(map[string]int)
+
+	// Pod UIDs using swap per QoS class, tallied before the burstable filter
+	swappingByQoS := map[string]map[string]struct{}{
+		"burstable":  {},
+		"besteffort": {},
+		"guaranteed": {},
+	}
+
+	for _, cgroupPath := range cgroupsResult.Cgroups {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// Extract pod UID from cgroup path
+		uid := cgroup.ExtractPodUID(cgroupPath)
+		if uid == "" {
+			klog.Warning("Could not extract pod UID from cgroup", "cgroupPath", cgroupPath)
+			continue
+		}
+
+		containerMetrics, err := scanner.GetContainerMetrics(cgroupPath)
+		if err != nil {
+			klog.Warning("Failed to get metrics for cgroup", "cgroupPath", cgroupPath, "err", err)
+			file := "unknown"
+			var readErr *cgroup.ReadError
+			if errors.As(err, &readErr) {
+				file = readErr.File
+			}
+			readErrors[file]++
+			continue
+		}
+
+		// Skip if not using swap
+		if containerMetrics.SwapCurrent == 0 {
+			continue
+		}
+
+		// A scope can linger briefly after its container exits; its swap would be double-counted
+		// alongside the restarted container's
+		if !scanner.HasProcesses(cgroupPath) {
+			klog.V(4).InfoS("Skipped cgroup, no live processes", "cgroupPath", cgroupPath)
+			continue
+		}
+
+		// Filter by QoS: only Burstable pods get swap in LimitedSwap mode
+		qos := cgroup.ExtractQoS(cgroupPath)
+		if pods, ok := swappingByQoS[qos]; ok {
+			pods[uid] = struct{}{}
+		}
+		if qos != "burstable" {
+			klog.V(4).InfoS("Skipped cgroup, QoS not burstable", "cgroupPath", cgroupPath, "qos", qos)
+			continue
+		}
+
+		if skip != nil && skip(uid, cgroupPath) {
+			continue
+		}
+
+		// Calculate swap percentage for THIS container
+		swapPercent := containerMetrics.SwapPercent()
+
+		limitEvents := containerMetrics.SwapEvents.Max + containerMetrics.SwapEvents.Fail
+
+		if existing := processedPods.get(uid); existing != nil {
+			// Pod already seen - take max swap percentage
+			// If ANY container exceeds threshold, the pod should be killed
+			existing.SwapBytes = addBytes(existing.SwapBytes, containerMetrics.SwapCurrent)
+			existing.MemoryBytes = addBytes(existing.MemoryBytes, containerMetrics.MemoryCurrent)
+			existing.SwapLimitEvents += limitEvents
+			existing.MemoryHighEvents += containerMetrics.MemoryEvents.High
+			if swapPercent > existing.SwapPercent {
+				existing.SwapPercent = swapPercent
+			}
+			if containerMetrics.PSI.FullAvg10 > existing.PSIFullAvg10 {
+				existing.PSIFullAvg10 = containerMetrics.PSI.FullAvg10
+			}
+			if pidsPercent := containerMetrics.PidsPercent(); pidsPercent > existing.PidsPercent {
+				existing.PidsPercent = pidsPercent
+			}
+			if ofLimit := containerMetrics.SwapOfLimitPercent(); ofLimit > existing.SwapOfLimitPercent {
+				existing.SwapOfLimitPercent = ofLimit
+			}
+			processedPods.fixed(uid)
+		} else {
+			// An evicted pod's later containers re-enter as a new candidate, so
+			// under the cap a pod's aggregates can be partial
+			cand := &PodCandidate{
+				UID:              uid,
+				SwapBytes:        containerMetrics.SwapCurrent,
+				MemoryBytes:      containerMetrics.MemoryCurrent,
+				SwapPercent:      swapPercent,
+				PSIFullAvg10:     containerMetrics.PSI.FullAvg10,
+				SwapLimitEvents:  limitEvents,
+				MemoryHighEvents: containerMetrics.MemoryEvents.High,
+				PidsPercent:      containerMetrics.PidsPercent(),
+
+				SwapOfLimitPercent: containerMetrics.SwapOfLimitPercent(),
+			}
+			if out := processedPods.offer(cand, maxCandidates); out != nil {
+				dropped[out.UID] = struct{}{}
+			}
+		}
+	}
+
+	result := &SwapScan{
+		SwappingByQoS: make(map[string]int, len(swappingByQoS)),
+		Unrecognized:  len(cgroupsResult.Unrecognized),
+		ReadErrors:    readErrors,
+	}
+	for qos, pods := range swappingByQoS {
+		result.SwappingByQoS[qos] = len(pods)
+	}
+	for _, cand := range processedPods.items {
+		result.Candidates = append(result.Candidates, *cand)
+		delete(dropped, cand.UID)
+	}
+	result.Dropped = len(dropped)
+
+	return result, nil
+}
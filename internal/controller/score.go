@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kill orderings for Config.SortBy
+const (
+	// SortByScore orders candidates by composite score (see ScoreWeights)
+	SortByScore = "score"
+	// SortByFootprint orders candidates by memory.current + swap bytes, so
+	// each kill frees as much memory as possible
+	SortByFootprint = "footprint"
+)
+
+// ScoreWeights controls how candidates are ordered for termination.
+//
+// Each signal is normalized to a fraction before weighting:
+//   - swap: SwapPercent / 100 (swap bytes as a fraction of memory.max; may exceed 1)
+//   - psi:  PSIFullAvg10 / 100 (fraction of the last 10s all tasks stalled on memory, 0-1)
+//
+// score = Swap*swap + PSI*psi. Candidates are killed in descending score order,
+// with equal scores ordered by ascending pod UID.
+type ScoreWeights struct {
+	Swap float64
+	PSI  float64
+}
+
+// DefaultScoreWeights reproduces ordering by swap percent alone
+var DefaultScoreWeights = ScoreWeights{Swap: 1, PSI: 0}
+
+// ParseScoreWeights parses a comma-separated list like "swap=0.7,psi=0.3".
+// Signals not listed get weight 0. An empty string returns DefaultScoreWeights.
+func ParseScoreWeights(value string) (ScoreWeights, error) {
+	if strings.TrimSpace(value) == "" {
+		return DefaultScoreWeights, nil
+	}
+
+	var weights ScoreWeights
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return ScoreWeights{}, fmt.Errorf("invalid score weight %q: expected name=value", part)
+		}
+
+		weight, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64)
+		if err != nil {
+			return ScoreWeights{}, fmt.Errorf("invalid score weight %q: %w", part, err)
+		}
+		if weight < 0 {
+			return ScoreWeights{}, fmt.Errorf("invalid score weight %q: must be >= 0", part)
+		}
+
+		switch strings.TrimSpace(kv[0]) {
+		case "swap":
+			weights.Swap = weight
+		case "psi":
+			weights.PSI = weight
+		default:
+			return ScoreWeights{}, fmt.Errorf("unknown score signal %q: expected swap or psi", kv[0])
+		}
+	}
+
+	if weights == (ScoreWeights{}) {
+		return ScoreWeights{}, fmt.Errorf("at least one score weight must be > 0")
+	}
+
+	return weights, nil
+}
+
+// score computes the composite kill-ordering score for a candidate. A NaN
+// input (e.g. a malformed PSI file) scores 0 so it can't break sort order.
+func (w ScoreWeights) score(cand PodCandidate) float64 {
+	return finite(w.Swap*finite(cand.SwapPercent)/100 + w.PSI*finite(cand.PSIFullAvg10)/100)
+}
+
+// footprint is the memory a candidate would free if killed: resident plus swapped
+func (cand PodCandidate) footprint() int64 {
+	return addBytes(cand.MemoryBytes, cand.SwapBytes)
+}
+
+// scoreWeights returns the configured weights, falling back to the defaults
+func (c *Controller) scoreWeights() ScoreWeights {
+	if c.config.ScoreWeights == (ScoreWeights{}) {
+		return DefaultScoreWeights
+	}
+	return c.config.ScoreWeights
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestParseScoreWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    ScoreWeights
+		wantErr bool
+	}{
+		{name: "empty uses default", value: "", want: DefaultScoreWeights},
+		{name: "swap only", value: "swap=1", want: ScoreWeights{Swap: 1}},
+		{name: "combined", value: "swap=0.7, psi=0.3", want: ScoreWeights{Swap: 0.7, PSI: 0.3}},
+		{name: "missing value", value: "swap", wantErr: true},
+		{name: "bad number", value: "swap=abc", wantErr: true},
+		{name: "negative", value: "swap=-1", wantErr: true},
+		{name: "unknown signal", value: "cpu=1", wantErr: true},
+		{name: "all zero", value: "swap=0,psi=0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseScoreWeights(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseScoreWeights(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseScoreWeights(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreWeights_Ordering(t *testing.T) {
+	// highSwap: 50% swap, no stall. highPSI: 10% swap, 80% full stall.
+	highSwap := PodCandidate{Name: "high-swap", SwapPercent: 50, PSIFullAvg10: 0}
+	highPSI := PodCandidate{Name: "high-psi", SwapPercent: 10, PSIFullAvg10: 80}
+
+	order := func(w ScoreWeights) string {
+		cands := []PodCandidate{highPSI, highSwap}
+		sort.Slice(cands, func(i, j int) bool {
+			return w.score(cands[i]) > w.score(cands[j])
+		})
+		return cands[0].Name
+	}
+
+	if got := order(DefaultScoreWeights); got != "high-swap" {
+		t.Errorf("default weights killed %s first, want high-swap", got)
+	}
+	if got := order(ScoreWeights{Swap: 0.3, PSI: 0.7}); got != "high-psi" {
+		t.Errorf("psi-heavy weights killed %s first, want high-psi", got)
+	}
+}
+
+func TestScoreWeights_NaN(t *testing.T) {
+	w := ScoreWeights{Swap: 1, PSI: 1}
+	if got := w.score(PodCandidate{SwapPercent: 20, PSIFullAvg10: math.NaN()}); got != 0.2 {
+		t.Errorf("score with NaN PSI = %v, want 0.2", got)
+	}
+	if got := w.score(PodCandidate{SwapPercent: math.Inf(1)}); got != 0 {
+		t.Errorf("score with Inf swap percent = %v, want 0", got)
+	}
+}
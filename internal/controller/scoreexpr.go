@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ScoreExprVariables maps each variable usable in a score expression to the
+// candidate field it reads
+var ScoreExprVariables = map[string]func(cand PodCandidate) float64{
+	"swap_percent":   func(cand PodCandidate) float64 { return cand.SwapPercent },
+	"psi_full_avg10": func(cand PodCandidate) float64 { return cand.PSIFullAvg10 },
+	"swap_bytes":     func(cand PodCandidate) float64 { return float64(cand.SwapBytes) },
+	"mem_bytes":      func(cand PodCandidate) float64 { return float64(cand.MemoryBytes) },
+}
+
+// ScoreExpr is a compiled kill-ordering expression such as
+// "swap_percent + psi_full_avg10 * 2". It supports numbers, the variables in
+// ScoreExprVariables, + - * /, unary minus and parentheses, with the usual
+// precedence. Candidates are killed in descending order of its value.
+type ScoreExpr struct {
+	source string
+	eval   func(cand PodCandidate) float64
+}
+
+// ParseScoreExpr compiles a score expression, failing on syntax errors and
+// unknown variables
+func ParseScoreExpr(value string) (*ScoreExpr, error) {
+	p := &exprParser{input: value}
+	p.next()
+	eval, err := p.parseSum()
+	if err != nil {
+		return nil, fmt.Errorf("invalid score expression %q: %w", value, err)
+	}
+	if p.tok != "" {
+		return nil, fmt.Errorf("invalid score expression %q: unexpected %q at offset %d", value, p.tok, p.tokPos)
+	}
+	return &ScoreExpr{source: value, eval: eval}, nil
+}
+
+// String returns the expression as given
+func (e *ScoreExpr) String() string {
+	return e.source
+}
+
+// score evaluates the expression for a candidate. A NaN or infinite result
+// (e.g. division by zero) scores 0 so it can't break sort order.
+func (e *ScoreExpr) score(cand PodCandidate) float64 {
+	return finite(e.eval(cand))
+}
+
+// exprParser is a recursive-descent parser over a one-token lookahead
+type exprParser struct {
+	input  string
+	pos    int
+	tok    string // current token, "" at end of input
+	tokPos int
+}
+
+// next advances to the next token: a number, an identifier, or a single
+// operator or parenthesis character
+func (p *exprParser) next() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+	p.tokPos = p.pos
+	if p.pos >= len(p.input) {
+		p.tok = ""
+		return
+	}
+
+	start := p.pos
+	switch c := p.input[p.pos]; {
+	case c >= '0' && c <= '9' || c == '.':
+		for p.pos < len(p.input) && (p.input[p.pos] >= '0' && p.input[p.pos] <= '9' || p.input[p.pos] == '.') {
+			p.pos++
+		}
+	case c == '_' || unicode.IsLetter(rune(c)):
+		for p.pos < len(p.input) && (p.input[p.pos] == '_' || unicode.IsLetter(rune(p.input[p.pos])) || unicode.IsDigit(rune(p.input[p.pos]))) {
+			p.pos++
+		}
+	default:
+		p.pos++
+	}
+	p.tok = p.input[start:p.pos]
+}
+
+// parseSum parses term (('+' | '-') term)*
+func (p *exprParser) parseSum() (func(PodCandidate) float64, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok == "+" || p.tok == "-" {
+		op := p.tok
+		p.next()
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		if op == "+" {
+			left = func(cand PodCandidate) float64 { return l(cand) + right(cand) }
+		} else {
+			left = func(cand PodCandidate) float64 { return l(cand) - right(cand) }
+		}
+	}
+	return left, nil
+}
+
+// parseProduct parses unary (('*' | '/') unary)*
+func (p *exprParser) parseProduct() (func(PodCandidate) float64, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok == "*" || p.tok == "/" {
+		op := p.tok
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		if op == "*" {
+			left = func(cand PodCandidate) float64 { return l(cand) * right(cand) }
+		} else {
+			left = func(cand PodCandidate) float64 { return l(cand) / right(cand) }
+		}
+	}
+	return left, nil
+}
+
+// parseUnary parses '-' unary | number | variable | '(' sum ')'
+func (p *exprParser) parseUnary() (func(PodCandidate) float64, error) {
+	tok, pos := p.tok, p.tokPos
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case tok == "-":
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(cand PodCandidate) float64 { return -operand(cand) }, nil
+	case tok == "(":
+		p.next()
+		inner, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok != ")" {
+			return nil, fmt.Errorf("missing ) for ( at offset %d", pos)
+		}
+		p.next()
+		return inner, nil
+	case tok[0] >= '0' && tok[0] <= '9' || tok[0] == '.':
+		value, err := strconv.ParseFloat(tok, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at offset %d", tok, pos)
+		}
+		p.next()
+		return func(PodCandidate) float64 { return value }, nil
+	case tok[0] == '_' || unicode.IsLetter(rune(tok[0])):
+		variable, ok := ScoreExprVariables[tok]
+		if !ok {
+			return nil, fmt.Errorf("unknown variable %q at offset %d: expected one of %s", tok, pos, strings.Join(scoreExprVariableNames(), ", "))
+		}
+		p.next()
+		return variable, nil
+	default:
+		return nil, fmt.Errorf("unexpected %q at offset %d", tok, pos)
+	}
+}
+
+// scoreExprVariableNames returns the variable names, sorted
+func scoreExprVariableNames() []string {
+	names := make([]string, 0, len(ScoreExprVariables))
+	for name := range ScoreExprVariables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
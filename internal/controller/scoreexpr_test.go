@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rophy/kube-soomkiller/internal/cgroup"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseScoreExpr(t *testing.T) {
+	cand := PodCandidate{SwapPercent: 20, PSIFullAvg10: 5, SwapBytes: 100, MemoryBytes: 300}
+
+	tests := []struct {
+		name    string
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{name: "variable", value: "swap_percent", want: 20},
+		{name: "precedence", value: "swap_percent + psi_full_avg10 * 2", want: 30},
+		{name: "parentheses", value: "(swap_percent + psi_full_avg10) * 2", want: 50},
+		{name: "left associative", value: "mem_bytes - swap_bytes - 100", want: 100},
+		{name: "division", value: "swap_bytes / 4", want: 25},
+		{name: "unary minus", value: "-swap_percent + 1.5", want: -18.5},
+		{name: "division by zero scores 0", value: "swap_bytes / 0", want: 0},
+		{name: "empty", value: "", wantErr: true},
+		{name: "unknown variable", value: "cpu_percent", wantErr: true},
+		{name: "dangling operator", value: "swap_percent +", wantErr: true},
+		{name: "unclosed parenthesis", value: "(swap_percent", wantErr: true},
+		{name: "trailing token", value: "swap_percent 2", wantErr: true},
+		{name: "bad number", value: "1.2.3", wantErr: true},
+		{name: "unknown operator", value: "swap_percent % 2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := ParseScoreExpr(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseScoreExpr(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := expr.score(cand); got != tt.want {
+				t.Errorf("ParseScoreExpr(%q).score() = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindAndKillOverThreshold_ScoreExpr(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// "hot" swaps the highest share of its limit; "big" holds far more memory
+	pods := []*corev1.Pod{
+		createPodWithUID("hot", "default", "test-node", "aaaa1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+		createPodWithUID("big", "default", "test-node", "bbbb1111-2222-3333-4444-555566667777", corev1.PodQOSBurstable),
+	}
+	createFakeCgroup(t, tmpDir, "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podaaaa1111_2222_3333_4444_555566667777.slice/cri-containerd-abc.scope", 100<<20, 256<<20)
+	bigScope := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podbbbb1111_2222_3333_4444_555566667777.slice/cri-containerd-def.scope"
+	createFakeCgroup(t, tmpDir, bigScope, 200<<20, 8<<30)
+	if err := os.WriteFile(filepath.Join(tmpDir, bigScope, "memory.current"), []byte(fmt.Sprintf("%d", 6<<30)), 0644); err != nil {
+		t.Fatalf("Failed to write memory.current: %v", err)
+	}
+
+	expr, err := ParseScoreExpr("mem_bytes")
+	if err != nil {
+		t.Fatalf("ParseScoreExpr() error = %v", err)
+	}
+	fakeClient := fake.NewSimpleClientset(pods[0], pods[1])
+	c := New(Config{
+		SwapThresholdPercent: 1.0,
+		KillTopN:             1,
+		ScoreExpr:            expr,
+		K8sClient:            fakeClient,
+		CgroupScanner:        cgroup.NewScanner(tmpDir),
+		PodInformer:          newTestPodInformer(t, pods...),
+	})
+
+	if err := c.findAndKillOverThreshold(context.Background()); err != nil {
+		t.Fatalf("findAndKillOverThreshold() error = %v", err)
+	}
+	// By swap percent "hot" would go first; the expression orders by memory
+	if _, err := fakeClient.CoreV1().Pods("default").Get(context.Background(), "big", metav1.GetOptions{}); err == nil {
+		t.Error("big was not killed, want it first by mem_bytes")
+	}
+	if _, err := fakeClient.CoreV1().Pods("default").Get(context.Background(), "hot", metav1.GetOptions{}); err != nil {
+		t.Errorf("hot was killed, want it spared by --kill-top-n: %v", err)
+	}
+}
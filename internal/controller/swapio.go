@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// DefaultSwapIOCounters are the /proc/vmstat counters summed into the swap
+// I/O rate when Config.SwapIOCounters is empty
+var DefaultSwapIOCounters = []string{"pswpin", "pswpout"}
+
+// swapIORate is the node-wide swap I/O rate between two /proc/vmstat samples
+type swapIORate struct {
+	in  float64 // events/sec of counters ending in "in" (pages swapped in by default)
+	out float64 // events/sec of all other counters (pages swapped out by default)
+}
+
+// swapIOCounters returns the configured vmstat counters
+func (c *Controller) swapIOCounters() []string {
+	if len(c.config.SwapIOCounters) == 0 {
+		return DefaultSwapIOCounters
+	}
+	return c.config.SwapIOCounters
+}
+
+// sampleSwapIO reads /proc/vmstat and returns the swap I/O rate since the
+// previous sample, summing the configured counters: those ending in "in"
+// (pswpin, pgpgin, zswpin) into the in rate and the rest into the out rate.
+// ok is false when there is no previous sample to compare against or the
+// counters could not be read.
+func (c *Controller) sampleSwapIO(now time.Time) (rate swapIORate, ok bool) {
+	counters, err := c.config.CgroupScanner.GetVmstatCounters(c.swapIOCounters())
+	if err != nil {
+		klog.V(2).InfoS("Failed to read swap I/O stats", "err", err)
+		return swapIORate{}, false
+	}
+
+	prev, prevAt := c.lastSwapIO, c.lastSwapIOAt
+	c.lastSwapIO, c.lastSwapIOAt = counters, now
+	if prev == nil {
+		return swapIORate{}, false
+	}
+
+	elapsed := now.Sub(prevAt).Seconds()
+	if elapsed <= 0 {
+		return swapIORate{}, false
+	}
+
+	for _, name := range c.swapIOCounters() {
+		delta := float64(counterDelta(prev[name], counters[name])) / elapsed
+		if strings.HasSuffix(name, "in") {
+			rate.in += delta
+		} else {
+			rate.out += delta
+		}
+	}
+	return rate, true
+}
+
+// counterDelta returns cur-prev for a cumulative counter, or 0 if the
+// counter went backwards (a reset) rather than wrapping to a huge value
+func counterDelta(prev, cur uint64) uint64 {
+	if cur < prev {
+		return 0
+	}
+	return cur - prev
+}
+
+// idleSinceLastPass reports whether the scan can be skipped with
+// --scan-only-on-swapio: no swap I/O since the previous pass and no pod
+// over threshold in it. Any uncertainty (first pass, unreadable counters)
+// scans.
+func (c *Controller) idleSinceLastPass(rate swapIORate, ok bool) bool {
+	return ok && rate.in == 0 && rate.out == 0 && c.lastOverThreshold == 0
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rophy/kube-soomkiller/internal/cgroup"
+	"github.com/rophy/kube-soomkiller/internal/metrics"
+)
+
+func writeVmstat(t *testing.T, procDir string, pswpin, pswpout uint64) {
+	t.Helper()
+	content := fmt.Sprintf("nr_free_pages 1000\npswpin %d\npswpout %d\n", pswpin, pswpout)
+	if err := os.WriteFile(filepath.Join(procDir, "vmstat"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write vmstat: %v", err)
+	}
+}
+
+func TestSampleSwapIO_CounterReset(t *testing.T) {
+	procDir := t.TempDir()
+	c := New(Config{CgroupScanner: cgroup.NewScannerWithOptions(t.TempDir(), cgroup.Options{ProcPath: procDir})})
+	now := time.Now()
+
+	writeVmstat(t, procDir, 1000, 2000)
+	if _, ok := c.sampleSwapIO(now); ok {
+		t.Error("first sample should have no rate")
+	}
+
+	writeVmstat(t, procDir, 1100, 2400)
+	rate, ok := c.sampleSwapIO(now.Add(2 * time.Second))
+	if !ok || rate.in != 50 || rate.out != 200 {
+		t.Errorf("rate = %+v (ok=%v), want {in:50 out:200}", rate, ok)
+	}
+
+	// Counters going backwards are a reset, not a wrap to ~2^64
+	writeVmstat(t, procDir, 10, 2500)
+	rate, ok = c.sampleSwapIO(now.Add(3 * time.Second))
+	if !ok || rate.in != 0 || rate.out != 100 {
+		t.Errorf("rate after pswpin reset = %+v (ok=%v), want {in:0 out:100}", rate, ok)
+	}
+}
+
+func TestSampleSwapIO_CustomCounters(t *testing.T) {
+	procDir := t.TempDir()
+	c := New(Config{
+		CgroupScanner:  cgroup.NewScannerWithOptions(t.TempDir(), cgroup.Options{ProcPath: procDir}),
+		SwapIOCounters: []string{"pswpin", "pswpout", "zswpin", "zswpout", "absent"},
+	})
+	now := time.Now()
+
+	write := func(zswpin, zswpout uint64) {
+		content := fmt.Sprintf("pswpin 100\npswpout 100\nzswpin %d\nzswpout %d\npgpgout 5000\n", zswpin, zswpout)
+		if err := os.WriteFile(filepath.Join(procDir, "vmstat"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write vmstat: %v", err)
+		}
+	}
+
+	write(10, 20)
+	c.sampleSwapIO(now)
+
+	// zswap activity never reaches pswpin/pswpout; unlisted counters are ignored
+	write(30, 80)
+	rate, ok := c.sampleSwapIO(now.Add(2 * time.Second))
+	if !ok || rate.in != 10 || rate.out != 30 {
+		t.Errorf("rate = %+v (ok=%v), want {in:10 out:30}", rate, ok)
+	}
+}
+
+func TestIdleSinceLastPass(t *testing.T) {
+	procDir := t.TempDir()
+	c := New(Config{CgroupScanner: cgroup.NewScannerWithOptions(t.TempDir(), cgroup.Options{ProcPath: procDir})})
+	now := time.Now()
+
+	writeVmstat(t, procDir, 100, 100)
+	if c.idleSinceLastPass(c.sampleSwapIO(now)) {
+		t.Error("first pass should always scan")
+	}
+	if !c.idleSinceLastPass(c.sampleSwapIO(now.Add(time.Second))) {
+		t.Error("idle node with nothing over threshold should skip")
+	}
+
+	writeVmstat(t, procDir, 100, 150)
+	if c.idleSinceLastPass(c.sampleSwapIO(now.Add(2 * time.Second))) {
+		t.Error("swap-out since last pass should scan")
+	}
+
+	// Pods over threshold last pass keep scanning even without swap I/O
+	c.lastOverThreshold = 1
+	if c.idleSinceLastPass(c.sampleSwapIO(now.Add(3 * time.Second))) {
+		t.Error("pods over threshold last pass should scan")
+	}
+}
+
+func TestReconcile_SwapIORateMetrics(t *testing.T) {
+	procDir := t.TempDir()
+	m := metrics.NewMetrics("test-node")
+	c := New(Config{
+		CgroupScanner: cgroup.NewScannerWithOptions(t.TempDir(), cgroup.Options{ProcPath: procDir}),
+		Metrics:       m,
+	})
+
+	// The scan itself fails (no kubepods.slice); rates are recorded regardless
+	writeVmstat(t, procDir, 100, 100)
+	c.reconcile(context.Background())
+	writeVmstat(t, procDir, 5100, 100)
+	c.reconcile(context.Background())
+
+	if got := testutil.ToFloat64(m.SwapInRate); got <= 0 {
+		t.Errorf("SwapInRate = %v, want > 0", got)
+	}
+	if got := testutil.ToFloat64(m.SwapOutRate); got != 0 {
+		t.Errorf("SwapOutRate = %v, want 0", got)
+	}
+}
@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// SwapPressureTaintKey is the NoSchedule taint added to the node while swap
+// pressure is sustained (see Config.TaintOnPressure)
+const SwapPressureTaintKey = "soomkiller.rophy.dev/swap-pressure"
+
+// nodeSwapPercent returns node-wide swap used as a percentage of total swap
+// across all active devices (0 when there is no swap)
+func (c *Controller) nodeSwapPercent() (float64, error) {
+	devices, err := c.config.CgroupScanner.GetSwapDevices()
+	if err != nil {
+		return 0, err
+	}
+
+	var size, used int64
+	for _, dev := range devices {
+		size += dev.Size
+		used += dev.Used
+	}
+	return percentOf(used, size), nil
+}
+
+// updateNodeTaint taints the node once node swap usage has stayed above
+// SwapActivationPercent for TaintAfter, and removes the taint as soon as
+// usage drops back below. Failures are logged and retried next reconcile so
+// they never block pod termination.
+func (c *Controller) updateNodeTaint(ctx context.Context, now time.Time) {
+	if !c.config.TaintOnPressure {
+		return
+	}
+
+	percent, err := c.nodeSwapPercent()
+	if err != nil {
+		klog.ErrorS(err, "Failed to read node swap usage for taint decision")
+		return
+	}
+
+	if percent > c.config.SwapActivationPercent {
+		if c.pressureSince.IsZero() {
+			c.pressureSince = now
+		}
+	} else {
+		c.pressureSince = time.Time{}
+	}
+
+	want := !c.pressureSince.IsZero() && now.Sub(c.pressureSince) >= c.config.TaintAfter
+	if c.taintSynced && want == c.nodeTainted {
+		return
+	}
+
+	if c.config.DryRun {
+		if want != c.nodeTainted {
+			klog.InfoS("Would update node swap pressure taint (dry-run)", "node", c.config.NodeName, "tainted", want, "nodeSwapPercent", percent)
+		}
+		c.nodeTainted = want
+		c.taintSynced = true
+		return
+	}
+
+	if err := c.setNodeTaint(ctx, want); err != nil {
+		klog.ErrorS(err, "Failed to update node swap pressure taint", "node", c.config.NodeName, "taint", want)
+		return
+	}
+
+	if want != c.nodeTainted || !c.taintSynced {
+		klog.InfoS("Updated node swap pressure taint", "node", c.config.NodeName, "tainted", want, "nodeSwapPercent", percent, "activationPercent", c.config.SwapActivationPercent)
+	}
+	c.nodeTainted = want
+	c.taintSynced = true
+}
+
+// setNodeTaint adds or removes the swap pressure taint on the node. It is a
+// no-op if the node is already in the desired state.
+func (c *Controller) setNodeTaint(ctx context.Context, tainted bool) error {
+	nodes := c.config.K8sClient.CoreV1().Nodes()
+	node, err := nodes.Get(ctx, c.config.NodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get node %s: %w", c.config.NodeName, err)
+	}
+
+	var taints []corev1.Taint
+	var present bool
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == SwapPressureTaintKey {
+			present = true
+			continue
+		}
+		taints = append(taints, taint)
+	}
+
+	if present == tainted {
+		return nil
+	}
+
+	if tainted {
+		added := metav1.NewTime(time.Now())
+		taints = append(taints, corev1.Taint{
+			Key:       SwapPressureTaintKey,
+			Effect:    corev1.TaintEffectNoSchedule,
+			TimeAdded: &added,
+		})
+	}
+
+	node = node.DeepCopy()
+	node.Spec.Taints = taints
+	if _, err := nodes.Update(ctx, node, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update taints on node %s: %w", c.config.NodeName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// warnState tracks the last warning emitted for a pod in the warn band
+type warnState struct {
+	lastEvent time.Time
+
+	// Labels the warning metric was emitted with, so it can be deleted later
+	namespace string
+	name      string
+}
+
+// inWarnBand reports whether a candidate is above the warn threshold but
+// below the kill threshold
+func (c *Controller) inWarnBand(cand PodCandidate) bool {
+	if c.config.WarnThresholdPercent <= 0 {
+		return false
+	}
+	return cand.SwapPercent > c.config.WarnThresholdPercent && cand.SwapPercent <= c.config.SwapThresholdPercent
+}
+
+// updateWarnings emits SoomkillWarning events for pods in the warn band (at
+// most once per pod per WarnInterval) and keeps the pods_warning gauge in
+// sync with the current band. Pods are never deleted here.
+func (c *Controller) updateWarnings(candidates []PodCandidate, now time.Time) {
+	if c.config.WarnThresholdPercent <= 0 {
+		return
+	}
+
+	warning := make(map[string]bool)
+	for _, cand := range candidates {
+		// Pods killed by other triggers this tick don't need a warning
+		if !c.inWarnBand(cand) || c.otherTriggerFired(cand) {
+			continue
+		}
+
+		pod := c.config.PodInformer.GetPodByUID(cand.UID)
+		if pod == nil || pod.DeletionTimestamp != nil {
+			continue
+		}
+		warning[cand.UID] = true
+
+		state := c.warnings[cand.UID]
+		state.namespace = pod.Namespace
+		state.name = pod.Name
+
+		if c.config.Metrics != nil {
+			c.config.Metrics.PodsWarning.WithLabelValues(pod.Namespace, pod.Name).Set(cand.SwapPercent)
+		}
+
+		if state.lastEvent.IsZero() || now.Sub(state.lastEvent) >= c.config.WarnInterval {
+			klog.V(2).InfoS("Pod over warning threshold", "pod", klog.KObj(pod), "swapPercent", cand.SwapPercent, "warnThresholdPercent", c.config.WarnThresholdPercent)
+			if c.config.EventRecorder != nil {
+				c.config.EventRecorder.Eventf(pod, corev1.EventTypeNormal, "SoomkillWarning",
+					"Pod %s swap usage %.1f%% exceeds warning threshold %.1f%% on node %s; it will be deleted above %.1f%%",
+					pod.Name, cand.SwapPercent, c.config.WarnThresholdPercent, c.config.NodeName, c.config.SwapThresholdPercent)
+			}
+			state.lastEvent = now
+		}
+
+		c.warnings[cand.UID] = state
+	}
+
+	for uid, state := range c.warnings {
+		if warning[uid] {
+			continue
+		}
+		// Left the band: drop the gauge series now, but remember the last
+		// event until the interval passes so oscillating pods don't spam
+		if c.config.Metrics != nil && state.name != "" {
+			c.config.Metrics.PodsWarning.DeleteLabelValues(state.namespace, state.name)
+		}
+		if now.Sub(state.lastEvent) >= c.config.WarnInterval {
+			delete(c.warnings, uid)
+		}
+	}
+}
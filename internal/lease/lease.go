@@ -0,0 +1,189 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// Lock is a node-scoped mutual-exclusion guard backed by a
+// coordination.k8s.io/v1 Lease. It is not leader election: each node has its
+// own lease, and a second controller for the same node simply fails to start.
+type Lock struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+	identity  string
+	duration  time.Duration
+
+	// now is overridable for tests
+	now func() time.Time
+}
+
+// LeaseName returns the lease name for a node
+func LeaseName(nodeName string) string {
+	return "kube-soomkiller-" + nodeName
+}
+
+// New creates a lock on the lease namespace/name held as identity
+func New(client kubernetes.Interface, namespace, name, identity string, duration time.Duration) *Lock {
+	return &Lock{
+		client:    client,
+		namespace: namespace,
+		name:      name,
+		identity:  identity,
+		duration:  duration,
+		now:       time.Now,
+	}
+}
+
+// Acquire takes the lease if it is free, expired, or already held by this
+// identity. It returns an error if another holder's lease is still valid.
+func (l *Lock) Acquire(ctx context.Context) error {
+	leases := l.client.CoordinationV1().Leases(l.namespace)
+
+	lease, err := leases.Get(ctx, l.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: l.name, Namespace: l.namespace},
+		}
+		l.claim(lease)
+		if _, err := leases.Create(ctx, lease, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create lease %s/%s: %w", l.namespace, l.name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get lease %s/%s: %w", l.namespace, l.name, err)
+	}
+
+	if holder := l.activeHolder(lease); holder != "" && holder != l.identity {
+		return fmt.Errorf("lease %s/%s is held by %s", l.namespace, l.name, holder)
+	}
+
+	lease = lease.DeepCopy()
+	l.claim(lease)
+	// Update uses the fetched resourceVersion, so a concurrent acquirer loses
+	if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update lease %s/%s: %w", l.namespace, l.name, err)
+	}
+	return nil
+}
+
+// Hold renews the lease every third of its duration until ctx is cancelled,
+// then releases it. Transient renewal errors are retried until the lease
+// would have expired. If the lease is taken by another holder or expires,
+// onLost is called and Hold returns without releasing.
+func (l *Lock) Hold(ctx context.Context, onLost func()) {
+	ticker := time.NewTicker(l.duration / 3)
+	defer ticker.Stop()
+
+	lastRenew := l.now()
+	for {
+		select {
+		case <-ctx.Done():
+			l.release()
+			return
+		case <-ticker.C:
+			err := l.renew(ctx)
+			if err == nil {
+				lastRenew = l.now()
+				continue
+			}
+			if ctx.Err() != nil {
+				continue
+			}
+			if errors.Is(err, errLeaseTaken) || l.now().Sub(lastRenew) >= l.duration {
+				klog.ErrorS(err, "Lost node lease", "lease", klog.KRef(l.namespace, l.name))
+				onLost()
+				return
+			}
+			klog.V(2).InfoS("Failed to renew node lease, will retry", "lease", klog.KRef(l.namespace, l.name), "err", err)
+		}
+	}
+}
+
+// errLeaseTaken means another identity now holds the lease
+var errLeaseTaken = errors.New("lease taken over")
+
+// renew extends the lease if this identity still holds it
+func (l *Lock) renew(ctx context.Context) error {
+	leases := l.client.CoordinationV1().Leases(l.namespace)
+
+	lease, err := leases.Get(ctx, l.name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get lease %s/%s: %w", l.namespace, l.name, err)
+	}
+	if holder := stringValue(lease.Spec.HolderIdentity); holder != l.identity {
+		return fmt.Errorf("%w by %q", errLeaseTaken, holder)
+	}
+
+	lease = lease.DeepCopy()
+	l.claim(lease)
+	if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update lease %s/%s: %w", l.namespace, l.name, err)
+	}
+	return nil
+}
+
+// release clears the holder so a replacement controller can start immediately
+func (l *Lock) release() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	leases := l.client.CoordinationV1().Leases(l.namespace)
+	lease, err := leases.Get(ctx, l.name, metav1.GetOptions{})
+	if err != nil || stringValue(lease.Spec.HolderIdentity) != l.identity {
+		return
+	}
+
+	lease = lease.DeepCopy()
+	lease.Spec.HolderIdentity = nil
+	lease.Spec.RenewTime = nil
+	if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		klog.InfoS("Failed to release node lease", "lease", klog.KRef(l.namespace, l.name), "err", err)
+		return
+	}
+	klog.InfoS("Released node lease", "lease", klog.KRef(l.namespace, l.name))
+}
+
+// claim sets this identity as the holder with a fresh renew time
+func (l *Lock) claim(lease *coordinationv1.Lease) {
+	now := metav1.NewMicroTime(l.now())
+	seconds := int32(l.duration.Seconds())
+
+	if stringValue(lease.Spec.HolderIdentity) != l.identity {
+		lease.Spec.AcquireTime = &now
+	}
+	lease.Spec.HolderIdentity = &l.identity
+	lease.Spec.LeaseDurationSeconds = &seconds
+	lease.Spec.RenewTime = &now
+}
+
+// activeHolder returns the lease holder, or "" if the lease is unheld or expired
+func (l *Lock) activeHolder(lease *coordinationv1.Lease) string {
+	holder := stringValue(lease.Spec.HolderIdentity)
+	if holder == "" || lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return ""
+	}
+
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	if l.now().After(expiry) {
+		return ""
+	}
+	return holder
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
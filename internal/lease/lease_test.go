@@ -0,0 +1,91 @@
+package lease
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAcquire(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx := context.Background()
+	now := time.Now()
+
+	first := New(client, "kube-soomkiller", LeaseName("node1"), "pod-a", 15*time.Second)
+	first.now = func() time.Time { return now }
+	if err := first.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire() on free lease error = %v", err)
+	}
+
+	// Re-acquiring as the same identity (e.g. after a container restart) succeeds
+	if err := first.Acquire(ctx); err != nil {
+		t.Errorf("Acquire() by current holder error = %v", err)
+	}
+
+	second := New(client, "kube-soomkiller", LeaseName("node1"), "pod-b", 15*time.Second)
+	second.now = func() time.Time { return now.Add(5 * time.Second) }
+	if err := second.Acquire(ctx); err == nil {
+		t.Error("Acquire() expected error while another holder's lease is valid")
+	}
+
+	// Leases are per node, so another node is unaffected
+	other := New(client, "kube-soomkiller", LeaseName("node2"), "pod-b", 15*time.Second)
+	if err := other.Acquire(ctx); err != nil {
+		t.Errorf("Acquire() on another node's lease error = %v", err)
+	}
+
+	// Once the first holder stops renewing, the lease can be taken over
+	second.now = func() time.Time { return now.Add(16 * time.Second) }
+	if err := second.Acquire(ctx); err != nil {
+		t.Errorf("Acquire() on expired lease error = %v", err)
+	}
+}
+
+func TestHold_ReleasesOnCancel(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	lock := New(client, "kube-soomkiller", LeaseName("node1"), "pod-a", 3*time.Second)
+	if err := lock.Acquire(context.Background()); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lock.Hold(ctx, func() { t.Error("onLost called for a held lease") })
+	}()
+	cancel()
+	<-done
+
+	// A released lease is immediately available to another identity
+	next := New(client, "kube-soomkiller", LeaseName("node1"), "pod-b", 3*time.Second)
+	if err := next.Acquire(context.Background()); err != nil {
+		t.Errorf("Acquire() after release error = %v", err)
+	}
+}
+
+func TestHold_LostToAnotherHolder(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	lock := New(client, "kube-soomkiller", LeaseName("node1"), "pod-a", 30*time.Millisecond)
+	if err := lock.Acquire(context.Background()); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	// Another controller forcibly takes the lease
+	thief := New(client, "kube-soomkiller", LeaseName("node1"), "pod-b", 30*time.Millisecond)
+	thief.now = func() time.Time { return time.Now().Add(time.Minute) }
+	if err := thief.Acquire(context.Background()); err != nil {
+		t.Fatalf("Acquire() by thief error = %v", err)
+	}
+
+	lost := make(chan struct{})
+	go lock.Hold(context.Background(), func() { close(lost) })
+
+	select {
+	case <-lost:
+	case <-time.After(time.Second):
+		t.Fatal("onLost not called after lease was taken over")
+	}
+}
@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"github.com/rophy/kube-soomkiller/internal/cgroup"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Resolution outcomes for a container cgroup (MappingEntry.Status)
+const (
+	MappingResolved          = "resolved"
+	MappingUnrecognized      = "unrecognized_scope"
+	MappingNotBurstable      = "not_burstable"
+	MappingNoPodUID          = "no_pod_uid"
+	MappingNoContainerID     = "no_container_id"
+	MappingPodNotFound       = "pod_not_found"
+	MappingContainerNotFound = "container_not_found"
+)
+
+// MappingEntry describes how a container cgroup was resolved to a pod and
+// container, and where resolution stopped if it failed
+type MappingEntry struct {
+	CgroupPath  string `json:"cgroupPath"`
+	PodUID      string `json:"podUID,omitempty"`
+	ContainerID string `json:"containerID,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Pod         string `json:"pod,omitempty"`
+	Container   string `json:"container,omitempty"`
+	Status      string `json:"status"`
+}
+
+// Mapping resolves every discovered container cgroup (and lists unrecognized
+// scopes) using the same steps as Collect, for debugging ID matching
+func (c *ContainerMetricsCollector) Mapping() ([]MappingEntry, error) {
+	result, err := c.scanner.FindPodCgroups()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]MappingEntry, 0, len(result.Cgroups)+len(result.Unrecognized))
+	for _, cgroupPath := range result.Cgroups {
+		entry, _ := c.resolve(cgroupPath)
+		entries = append(entries, entry)
+	}
+	for _, cgroupPath := range result.Unrecognized {
+		entries = append(entries, MappingEntry{
+			CgroupPath: cgroupPath,
+			PodUID:     cgroup.ExtractPodUID(cgroupPath),
+			Status:     MappingUnrecognized,
+		})
+	}
+
+	return entries, nil
+}
+
+// resolve maps a container cgroup to its pod and container name. The pod is
+// returned only when Status is MappingResolved.
+func (c *ContainerMetricsCollector) resolve(cgroupPath string) (MappingEntry, *corev1.Pod) {
+	entry := MappingEntry{CgroupPath: cgroupPath}
+
+	// Only burstable pods use swap in LimitedSwap mode
+	if !cgroup.IsBurstable(cgroupPath) {
+		entry.Status = MappingNotBurstable
+		return entry, nil
+	}
+
+	// Extract pod UID and container ID from cgroup path
+	entry.PodUID = cgroup.ExtractPodUID(cgroupPath)
+	entry.ContainerID = c.scanner.ExtractContainerID(cgroupPath)
+	if entry.PodUID == "" {
+		entry.Status = MappingNoPodUID
+		return entry, nil
+	}
+	if entry.ContainerID == "" {
+		entry.Status = MappingNoContainerID
+		return entry, nil
+	}
+
+	// Look up pod to get namespace, pod name, and container name
+	pod := c.podLookup.GetPodByUID(entry.PodUID)
+	if pod == nil {
+		entry.Status = MappingPodNotFound
+		return entry, nil
+	}
+	entry.Namespace = pod.Namespace
+	entry.Pod = pod.Name
+
+	// Find container name by matching container ID
+	entry.Container = FindContainerName(pod, entry.ContainerID)
+	if entry.Container == "" {
+		entry.Status = MappingContainerNotFound
+		return entry, nil
+	}
+
+	entry.Status = MappingResolved
+	return entry, pod
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rophy/kube-soomkiller/internal/cgroup"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// staticLookup is a PodLookup backed by a map of UID to pod
+type staticLookup map[string]*corev1.Pod
+
+func (l staticLookup) GetPodByUID(uid string) *corev1.Pod {
+	return l[uid]
+}
+
+func TestMapping(t *testing.T) {
+	root := t.TempDir()
+	podSlice := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podaaaa1111_2222_3333_4444_555566667777.slice"
+	for _, dir := range []string{
+		podSlice + "/cri-containerd-abc123.scope",
+		podSlice + "/cri-containerd-def456.scope",
+		podSlice + "/kata-xyz.scope",
+		"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podbbbb1111_2222_3333_4444_555566667777.slice/cri-containerd-fff.scope",
+		"kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-podcccc1111_2222_3333_4444_555566667777.slice/cri-containerd-eee.scope",
+	} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("Failed to create cgroup dir: %v", err)
+		}
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: types.UID("aaaa1111-2222-3333-4444-555566667777")},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "main", ContainerID: "containerd://abc123"}},
+		},
+	}
+	collector := NewContainerMetricsCollector(cgroup.NewScanner(root), staticLookup{string(pod.UID): pod}, "test-node", 1.0)
+
+	entries, err := collector.Mapping()
+	if err != nil {
+		t.Fatalf("Mapping() error = %v", err)
+	}
+
+	byPath := make(map[string]MappingEntry)
+	for _, entry := range entries {
+		byPath[entry.CgroupPath] = entry
+	}
+
+	tests := []struct {
+		path      string
+		status    string
+		container string
+	}{
+		{podSlice + "/cri-containerd-abc123.scope", MappingResolved, "main"},
+		{podSlice + "/cri-containerd-def456.scope", MappingContainerNotFound, ""},
+		{podSlice + "/kata-xyz.scope", MappingUnrecognized, ""},
+		{"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podbbbb1111_2222_3333_4444_555566667777.slice/cri-containerd-fff.scope", MappingPodNotFound, ""},
+		{"kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-podcccc1111_2222_3333_4444_555566667777.slice/cri-containerd-eee.scope", MappingNotBurstable, ""},
+	}
+
+	if len(entries) != len(tests) {
+		t.Errorf("Mapping() returned %d entries, want %d", len(entries), len(tests))
+	}
+	for _, tt := range tests {
+		entry, ok := byPath[tt.path]
+		if !ok {
+			t.Errorf("no mapping entry for %s", tt.path)
+			continue
+		}
+		if entry.Status != tt.status || entry.Container != tt.container {
+			t.Errorf("mapping for %s = {status: %s, container: %q}, want {status: %s, container: %q}", tt.path, entry.Status, entry.Container, tt.status, tt.container)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rophy/kube-soomkiller/internal/cgroup"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
 )
 
 const (
@@ -18,7 +19,71 @@ type Metrics struct {
 
 	// Pod termination metrics
 	PodsKilledTotal   prometheus.Counter
+	PodsWouldKill     prometheus.Counter
 	LastKillTimestamp prometheus.Gauge
+	PodsSkippedTotal  *prometheus.CounterVec
+
+	// 1 while the node's pause annotation holds kills, else 0
+	KillsPaused prometheus.Gauge
+
+	// Kills deferred because the pod's namespace used up its kill budget, by namespace
+	KillsRateLimited *prometheus.CounterVec
+
+	// The last killed pod, labeled by namespace and pod (value is the kill's
+	// Unix timestamp). Only the latest kill has a series.
+	LastKillInfo *prometheus.GaugeVec
+
+	// Time from a kill's delete call until the pod leaves the informer cache
+	KillToRemoval prometheus.Histogram
+
+	// Candidate metrics (pods using swap vs. pods over the kill threshold)
+	CandidatePodsCount prometheus.Gauge
+	PodsOverThreshold  prometheus.Gauge
+
+	// Pods using swap in the last scan by QoS class, including classes never killed
+	CandidatesByQoS *prometheus.GaugeVec
+
+	// Container scopes that match no runtime prefix (invisible to the controller)
+	UnrecognizedCgroups prometheus.Gauge
+
+	// Container runtimes detected at startup (always 1, labelled by runtime)
+	RuntimeInfo *prometheus.GaugeVec
+
+	// Node swap I/O rates between reconcile passes (pages/sec, from /proc/vmstat)
+	SwapInRate  prometheus.Gauge
+	SwapOutRate prometheus.Gauge
+
+	// Node pages reclaimed per second between reconcile passes (pgsteal_*,
+	// from /proc/vmstat), only sampled with --defer-on-active-reclaim
+	ReclaimRate prometheus.Gauge
+
+	// Reconcile loop metrics
+	ReconcileDuration      prometheus.Histogram
+	ReconcilesTotal        prometheus.Counter
+	ReconcileErrorsTotal   prometheus.Counter
+	ScanDuration           prometheus.Histogram
+	ScanTimeoutsTotal      prometheus.Counter
+	LastReconcileTimestamp prometheus.Gauge
+
+	// Containers skipped by a reconcile scan because a cgroup file couldn't be read, by file
+	ScanReadErrors *prometheus.CounterVec
+
+	// Node-scoped pod informer health (a cluster-wide watch shows up as a huge cache)
+	InformerPodsCached     prometheus.Gauge
+	InformerLastResyncTime prometheus.Gauge
+
+	// Per-pod swap growth rate and swap percent, labeled by namespace and pod.
+	// Series are deleted when a pod stops using swap or disappears.
+	PodSwapGrowthRate *prometheus.GaugeVec
+	PodSwapPercent    *prometheus.GaugeVec
+	PodSwapNodeFrac   *prometheus.GaugeVec
+	PodSwapOfLimit    *prometheus.GaugeVec
+
+	// Pods between the warn and kill thresholds, labeled by namespace and pod (value is swap %)
+	PodsWarning *prometheus.GaugeVec
+
+	// Build metadata, labeled by version and goversion (always 1)
+	BuildInfo *prometheus.GaugeVec
 
 	// Configuration metrics
 	ConfigSwapThresholdPercent prometheus.Gauge
@@ -43,6 +108,183 @@ func NewMetrics(nodeName string) *Metrics {
 			Help:        "Unix timestamp of the last pod kill",
 			ConstLabels: nodeLabel,
 		}),
+		PodsWouldKill: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "pods_would_kill_total",
+			Help:        "Total number of pods that would have been killed but for dry-run, the startup grace period or a pause, counted once per pod",
+			ConstLabels: nodeLabel,
+		}),
+		KillsPaused: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "kills_paused",
+			Help:        "1 while kills are paused by the node's soomkiller.rophy.dev/pause annotation, else 0",
+			ConstLabels: nodeLabel,
+		}),
+		LastKillInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "last_kill_info",
+			Help:        "Unix timestamp of the last pod kill, labeled by the killed pod",
+			ConstLabels: nodeLabel,
+		}, []string{"namespace", "pod"}),
+		KillToRemoval: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "kill_to_removal_seconds",
+			Help:        "Time from deleting or evicting a pod until it disappeared from the informer cache",
+			ConstLabels: nodeLabel,
+			Buckets:     prometheus.ExponentialBuckets(0.5, 2, 10), // 0.5s to ~4m
+		}),
+		KillsRateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "kills_ratelimited_total",
+			Help:        "Total number of kills deferred because the pod's namespace kill budget was exhausted",
+			ConstLabels: nodeLabel,
+		}, []string{"namespace"}),
+		PodsSkippedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "pods_skipped_total",
+			Help:        "Total number of over-threshold pods spared, by reason",
+			ConstLabels: nodeLabel,
+		}, []string{"reason"}),
+		CandidatePodsCount: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "candidate_pods",
+			Help:        "Number of burstable pods using swap in the last scan",
+			ConstLabels: nodeLabel,
+		}),
+		PodsOverThreshold: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "pods_over_threshold",
+			Help:        "Number of pods over the kill threshold in the last scan, before protection filters",
+			ConstLabels: nodeLabel,
+		}),
+		CandidatesByQoS: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "candidates_by_qos",
+			Help:        "Number of pods using swap in the last scan, by QoS class",
+			ConstLabels: nodeLabel,
+		}, []string{"qos"}),
+		UnrecognizedCgroups: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "unrecognized_cgroups",
+			Help:        "Number of .scope directories under kubepods.slice not matching any runtime prefix in the last scan",
+			ConstLabels: nodeLabel,
+		}),
+		RuntimeInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "runtime_info",
+			Help:        "Container runtimes detected at startup, one series per runtime (value is always 1)",
+			ConstLabels: nodeLabel,
+		}, []string{"runtime"}),
+		SwapInRate: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "node_swap_in_rate",
+			Help:        "Pages swapped in per second since the previous reconcile",
+			ConstLabels: nodeLabel,
+		}),
+		SwapOutRate: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "node_swap_out_rate",
+			Help:        "Pages swapped out per second since the previous reconcile",
+			ConstLabels: nodeLabel,
+		}),
+		ReclaimRate: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "node_reclaim_rate",
+			Help:        "Pages reclaimed per second (pgsteal_kswapd + pgsteal_direct) since the previous reconcile",
+			ConstLabels: nodeLabel,
+		}),
+		ReconcileDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "reconcile_duration_seconds",
+			Help:        "Time taken by a single reconcile pass",
+			ConstLabels: nodeLabel,
+			Buckets:     prometheus.ExponentialBuckets(0.001, 2, 14), // 1ms to ~8s
+		}),
+		ReconcilesTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "reconciles_total",
+			Help:        "Total number of reconcile passes started",
+			ConstLabels: nodeLabel,
+		}),
+		ReconcileErrorsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "reconcile_errors_total",
+			Help:        "Total number of reconcile passes that returned an error",
+			ConstLabels: nodeLabel,
+		}),
+		ScanDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "scan_duration_seconds",
+			Help:        "Time taken to scan cgroups for swap usage",
+			ConstLabels: nodeLabel,
+			Buckets:     prometheus.ExponentialBuckets(0.001, 2, 14), // 1ms to ~8s
+		}),
+		ScanTimeoutsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "scan_timeouts_total",
+			Help:        "Total number of cgroup scans abandoned after exceeding the scan timeout",
+			ConstLabels: nodeLabel,
+		}),
+		ScanReadErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "scan_read_errors_total",
+			Help:        "Total number of containers skipped by a reconcile cgroup scan because a required cgroup file could not be read, by file",
+			ConstLabels: nodeLabel,
+		}, []string{"file"}),
+		LastReconcileTimestamp: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "last_reconcile_timestamp_seconds",
+			Help:        "Unix timestamp of the last successful reconcile",
+			ConstLabels: nodeLabel,
+		}),
+		InformerPodsCached: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "informer_pods_cached",
+			Help:        "Number of pods in the node-scoped informer cache",
+			ConstLabels: nodeLabel,
+		}),
+		InformerLastResyncTime: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "informer_last_resync_timestamp_seconds",
+			Help:        "Unix timestamp of the last pod informer resync (0 until the first one)",
+			ConstLabels: nodeLabel,
+		}),
+		PodSwapGrowthRate: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "pod_swap_growth_bytes_per_second",
+			Help:        "Rate of change of pod swap usage since the previous reconcile",
+			ConstLabels: nodeLabel,
+		}, []string{"namespace", "pod"}),
+		PodSwapPercent: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "pod_swap_percent",
+			Help:        "Pod swap usage as a percentage of memory limit (max across containers), as compared to the kill threshold",
+			ConstLabels: nodeLabel,
+		}, []string{"namespace", "pod"}),
+		PodSwapNodeFrac: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "pod_swap_node_fraction",
+			Help:        "Pod swap bytes as a fraction of node SwapTotal from /proc/meminfo (0-1)",
+			ConstLabels: nodeLabel,
+		}, []string{"namespace", "pod"}),
+		PodSwapOfLimit: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "pod_swap_of_limit_percent",
+			Help:        "Pod swap usage as a percentage of memory.swap.max (max across containers), for pods with a swap limit",
+			ConstLabels: nodeLabel,
+		}, []string{"namespace", "pod"}),
+		PodsWarning: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "pods_warning",
+			Help:        "Swap usage percent of pods above the warning threshold but below the kill threshold",
+			ConstLabels: nodeLabel,
+		}, []string{"namespace", "pod"}),
+		BuildInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "build_info",
+			Help:        "Build information for the running binary, always 1",
+			ConstLabels: nodeLabel,
+		}, []string{"version", "goversion"}),
 		ConfigSwapThresholdPercent: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace:   namespace,
 			Name:        "config_swap_threshold_percent",
@@ -58,11 +300,46 @@ func NewMetrics(nodeName string) *Metrics {
 	}
 }
 
-// Register registers all metrics with prometheus
+// Register registers all metrics with the default prometheus registry
 func (m *Metrics) Register() {
-	prometheus.MustRegister(
+	m.RegisterWith(prometheus.DefaultRegisterer)
+}
+
+// RegisterWith registers all metrics with reg. It panics if any metric is
+// already registered there.
+func (m *Metrics) RegisterWith(reg prometheus.Registerer) {
+	reg.MustRegister(
 		m.PodsKilledTotal,
+		m.PodsWouldKill,
+		m.KillsPaused,
 		m.LastKillTimestamp,
+		m.LastKillInfo,
+		m.KillToRemoval,
+		m.PodsSkippedTotal,
+		m.KillsRateLimited,
+		m.CandidatePodsCount,
+		m.PodsOverThreshold,
+		m.CandidatesByQoS,
+		m.UnrecognizedCgroups,
+		m.RuntimeInfo,
+		m.SwapInRate,
+		m.SwapOutRate,
+		m.ReclaimRate,
+		m.ReconcileDuration,
+		m.ReconcilesTotal,
+		m.ReconcileErrorsTotal,
+		m.ScanDuration,
+		m.ScanTimeoutsTotal,
+		m.ScanReadErrors,
+		m.LastReconcileTimestamp,
+		m.InformerPodsCached,
+		m.InformerLastResyncTime,
+		m.PodSwapGrowthRate,
+		m.PodSwapPercent,
+		m.PodSwapNodeFrac,
+		m.PodSwapOfLimit,
+		m.PodsWarning,
+		m.BuildInfo,
 		m.ConfigSwapThresholdPercent,
 		m.ConfigDryRun,
 	)
@@ -113,9 +390,82 @@ func (c *SwapIOCollector) Collect(ch chan<- prometheus.Metric) {
 	ch <- prometheus.MustNewConstMetric(c.pswpOutDesc, prometheus.CounterValue, float64(stats.PswpOut))
 }
 
-// RegisterSwapIOCollector registers the swap I/O collector
+// RegisterSwapIOCollector registers the swap I/O collector with the default registry
 func RegisterSwapIOCollector(scanner *cgroup.Scanner, nodeName string) {
-	prometheus.MustRegister(NewSwapIOCollector(scanner, nodeName))
+	RegisterSwapIOCollectorWith(prometheus.DefaultRegisterer, scanner, nodeName)
+}
+
+// RegisterSwapIOCollectorWith registers the swap I/O collector with reg
+func RegisterSwapIOCollectorWith(reg prometheus.Registerer, scanner *cgroup.Scanner, nodeName string) {
+	reg.MustRegister(NewSwapIOCollector(scanner, nodeName))
+}
+
+// SwapDeviceCollector exposes node-level swap device sizes from /proc/swaps
+// and vm.swappiness
+type SwapDeviceCollector struct {
+	scanner        *cgroup.Scanner
+	sizeDesc       *prometheus.Desc
+	usedDesc       *prometheus.Desc
+	swappinessDesc *prometheus.Desc
+}
+
+// NewSwapDeviceCollector creates a collector that exposes swap device sizes
+func NewSwapDeviceCollector(scanner *cgroup.Scanner, nodeName string) *SwapDeviceCollector {
+	labels := []string{"device", "type"}
+	nodeLabel := prometheus.Labels{"node": nodeName}
+
+	return &SwapDeviceCollector{
+		scanner: scanner,
+		sizeDesc: prometheus.NewDesc(
+			namespace+"_node_swap_device_size_bytes",
+			"Size of each active swap device (from /proc/swaps)",
+			labels, nodeLabel,
+		),
+		usedDesc: prometheus.NewDesc(
+			namespace+"_node_swap_device_used_bytes",
+			"Used space on each active swap device (from /proc/swaps)",
+			labels, nodeLabel,
+		),
+		swappinessDesc: prometheus.NewDesc(
+			namespace+"_node_swappiness",
+			"Kernel vm.swappiness (from /proc/sys/vm/swappiness)",
+			nil, nodeLabel,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *SwapDeviceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.sizeDesc
+	ch <- c.usedDesc
+	ch <- c.swappinessDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *SwapDeviceCollector) Collect(ch chan<- prometheus.Metric) {
+	if swappiness, err := c.scanner.GetSwappiness(); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.swappinessDesc, prometheus.GaugeValue, float64(swappiness))
+	}
+
+	devices, err := c.scanner.GetSwapDevices()
+	if err != nil {
+		return
+	}
+
+	for _, dev := range devices {
+		ch <- prometheus.MustNewConstMetric(c.sizeDesc, prometheus.GaugeValue, float64(dev.Size), dev.Name, dev.Type)
+		ch <- prometheus.MustNewConstMetric(c.usedDesc, prometheus.GaugeValue, float64(dev.Used), dev.Name, dev.Type)
+	}
+}
+
+// RegisterSwapDeviceCollector registers the swap device collector with the default registry
+func RegisterSwapDeviceCollector(scanner *cgroup.Scanner, nodeName string) {
+	RegisterSwapDeviceCollectorWith(prometheus.DefaultRegisterer, scanner, nodeName)
+}
+
+// RegisterSwapDeviceCollectorWith registers the swap device collector with reg
+func RegisterSwapDeviceCollectorWith(reg prometheus.Registerer, scanner *cgroup.Scanner, nodeName string) {
+	reg.MustRegister(NewSwapDeviceCollector(scanner, nodeName))
 }
 
 // PodLookup is an interface for looking up pods by UID
@@ -129,21 +479,37 @@ type ContainerMetricsCollector struct {
 	podLookup PodLookup
 	nodeName  string
 
+	// Kill threshold (--swap-threshold-percent) for swapOverLimitDesc
+	thresholdPercent float64
+
 	swapBytesDesc     *prometheus.Desc
 	swapMaxDesc       *prometheus.Desc
 	memoryCurrentDesc *prometheus.Desc
 	memoryMaxDesc     *prometheus.Desc
+	zswapDesc         *prometheus.Desc
+	swapCachedDesc    *prometheus.Desc
+	swapEventsDesc    *prometheus.Desc
+	swapPercentDesc   *prometheus.Desc
+	pidsCurrentDesc   *prometheus.Desc
+	pidsMaxDesc       *prometheus.Desc
+	memoryHighDesc    *prometheus.Desc
+	swapOverLimitDesc *prometheus.Desc
+
+	// Cgroups of known pods skipped because no container status matched their ID
+	unmatchedDesc *prometheus.Desc
 }
 
-// NewContainerMetricsCollector creates a collector for per-container metrics
-func NewContainerMetricsCollector(scanner *cgroup.Scanner, podLookup PodLookup, nodeName string) *ContainerMetricsCollector {
+// NewContainerMetricsCollector creates a collector for per-container metrics.
+// thresholdPercent is the controller's kill threshold, used to flag containers over it.
+func NewContainerMetricsCollector(scanner *cgroup.Scanner, podLookup PodLookup, nodeName string, thresholdPercent float64) *ContainerMetricsCollector {
 	labels := []string{"namespace", "pod", "container"}
 	nodeLabel := prometheus.Labels{"node": nodeName}
 
 	return &ContainerMetricsCollector{
-		scanner:   scanner,
-		podLookup: podLookup,
-		nodeName:  nodeName,
+		scanner:          scanner,
+		podLookup:        podLookup,
+		nodeName:         nodeName,
+		thresholdPercent: thresholdPercent,
 		swapBytesDesc: prometheus.NewDesc(
 			namespace+"_container_swap_bytes",
 			"Current swap usage in bytes per container",
@@ -164,6 +530,51 @@ func NewContainerMetricsCollector(scanner *cgroup.Scanner, podLookup PodLookup,
 			"Memory limit in bytes per container",
 			labels, nodeLabel,
 		),
+		zswapDesc: prometheus.NewDesc(
+			namespace+"_container_zswap_bytes",
+			"Memory consumed by the compressed swap pool per container (memory.stat zswap)",
+			labels, nodeLabel,
+		),
+		swapCachedDesc: prometheus.NewDesc(
+			namespace+"_container_swapcached_bytes",
+			"Swapped-out memory also cached in RAM per container (memory.stat swapcached)",
+			labels, nodeLabel,
+		),
+		swapPercentDesc: prometheus.NewDesc(
+			namespace+"_container_swap_percent",
+			"Swap usage as a percentage of memory.max (memory.swap.max if memory is unlimited), as used for kill decisions",
+			labels, nodeLabel,
+		),
+		pidsCurrentDesc: prometheus.NewDesc(
+			namespace+"_container_pids_current",
+			"Number of tasks per container (pids.current, 0 if the pids controller is not enabled)",
+			labels, nodeLabel,
+		),
+		pidsMaxDesc: prometheus.NewDesc(
+			namespace+"_container_pids_max",
+			"Task limit per container (pids.max)",
+			labels, nodeLabel,
+		),
+		swapOverLimitDesc: prometheus.NewDesc(
+			namespace+"_container_swap_over_limit",
+			"1 if the container alone exceeds the swap kill threshold percent, 0 otherwise",
+			labels, nodeLabel,
+		),
+		memoryHighDesc: prometheus.NewDesc(
+			namespace+"_container_memory_high_events_total",
+			"Times the container was throttled at its memory.high watermark (memory.events high)",
+			labels, nodeLabel,
+		),
+		swapEventsDesc: prometheus.NewDesc(
+			namespace+"_container_swap_events_total",
+			"Swap limit events per container from memory.swap.events, by type (high, max, fail)",
+			append(labels, "type"), nodeLabel,
+		),
+		unmatchedDesc: prometheus.NewDesc(
+			namespace+"_container_unmatched",
+			"Container cgroups of known pods currently skipped during collection because no container status matched their ID",
+			nil, nodeLabel,
+		),
 	}
 }
 
@@ -173,6 +584,15 @@ func (c *ContainerMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.swapMaxDesc
 	ch <- c.memoryCurrentDesc
 	ch <- c.memoryMaxDesc
+	ch <- c.zswapDesc
+	ch <- c.swapCachedDesc
+	ch <- c.swapEventsDesc
+	ch <- c.swapPercentDesc
+	ch <- c.pidsCurrentDesc
+	ch <- c.pidsMaxDesc
+	ch <- c.memoryHighDesc
+	ch <- c.swapOverLimitDesc
+	ch <- c.unmatchedDesc
 }
 
 // Collect implements prometheus.Collector - scans cgroups on each scrape
@@ -182,31 +602,24 @@ func (c *ContainerMetricsCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	for _, cgroupPath := range result.Cgroups {
-		// Only burstable pods use swap in LimitedSwap mode
-		if !cgroup.IsBurstable(cgroupPath) {
-			continue
-		}
+	unmatched := 0
+	// Emitted last so it reflects every cgroup this scrape saw
+	defer func() {
+		ch <- prometheus.MustNewConstMetric(c.unmatchedDesc, prometheus.GaugeValue, float64(unmatched))
+	}()
 
-		// Extract pod UID and container ID from cgroup path
-		podUID := cgroup.ExtractPodUID(cgroupPath)
-		containerID := cgroup.ExtractContainerID(cgroupPath)
-		if podUID == "" || containerID == "" {
-			continue
+	for _, cgroupPath := range result.Cgroups {
+		// Skip cgroups that don't resolve to a known container (see Mapping for why)
+		entry, pod := c.resolve(cgroupPath)
+		if entry.Status == MappingContainerNotFound {
+			// Usually a container ID format or truncation mismatch; the swap would otherwise vanish silently
+			unmatched++
+			klog.V(2).InfoS("Container cgroup matched no container status", "cgroupPath", cgroupPath, "podUID", entry.PodUID, "containerID", entry.ContainerID, "pod", klog.KRef(entry.Namespace, entry.Pod))
 		}
-
-		// Look up pod to get namespace, pod name, and container name
-		pod := c.podLookup.GetPodByUID(podUID)
 		if pod == nil {
 			continue
 		}
 
-		// Find container name by matching container ID
-		containerName := findContainerName(pod, containerID)
-		if containerName == "" {
-			continue
-		}
-
 		// Get container metrics from cgroup
 		metrics, err := c.scanner.GetContainerMetrics(cgroupPath)
 		if err != nil {
@@ -214,7 +627,7 @@ func (c *ContainerMetricsCollector) Collect(ch chan<- prometheus.Metric) {
 		}
 
 		// Emit metrics
-		labels := []string{pod.Namespace, pod.Name, containerName}
+		labels := []string{pod.Namespace, pod.Name, entry.Container}
 
 		ch <- prometheus.MustNewConstMetric(c.swapBytesDesc, prometheus.GaugeValue,
 			float64(metrics.SwapCurrent), labels...)
@@ -224,11 +637,36 @@ func (c *ContainerMetricsCollector) Collect(ch chan<- prometheus.Metric) {
 			float64(metrics.MemoryCurrent), labels...)
 		ch <- prometheus.MustNewConstMetric(c.memoryMaxDesc, prometheus.GaugeValue,
 			float64(metrics.MemoryMax), labels...)
+		ch <- prometheus.MustNewConstMetric(c.swapPercentDesc, prometheus.GaugeValue,
+			metrics.SwapPercent(), labels...)
+		overLimit := 0.0
+		if metrics.SwapPercent() > c.thresholdPercent {
+			overLimit = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.swapOverLimitDesc, prometheus.GaugeValue,
+			overLimit, labels...)
+		ch <- prometheus.MustNewConstMetric(c.zswapDesc, prometheus.GaugeValue,
+			float64(metrics.Stat.Zswap), labels...)
+		ch <- prometheus.MustNewConstMetric(c.swapCachedDesc, prometheus.GaugeValue,
+			float64(metrics.Stat.SwapCached), labels...)
+		ch <- prometheus.MustNewConstMetric(c.pidsCurrentDesc, prometheus.GaugeValue,
+			float64(metrics.PidsCurrent), labels...)
+		ch <- prometheus.MustNewConstMetric(c.pidsMaxDesc, prometheus.GaugeValue,
+			float64(metrics.PidsMax), labels...)
+		ch <- prometheus.MustNewConstMetric(c.memoryHighDesc, prometheus.CounterValue,
+			float64(metrics.MemoryEvents.High), labels...)
+		ch <- prometheus.MustNewConstMetric(c.swapEventsDesc, prometheus.CounterValue,
+			float64(metrics.SwapEvents.High), append(labels, "high")...)
+		ch <- prometheus.MustNewConstMetric(c.swapEventsDesc, prometheus.CounterValue,
+			float64(metrics.SwapEvents.Max), append(labels, "max")...)
+		ch <- prometheus.MustNewConstMetric(c.swapEventsDesc, prometheus.CounterValue,
+			float64(metrics.SwapEvents.Fail), append(labels, "fail")...)
 	}
 }
 
-// findContainerName finds the container name by matching container ID in pod status
-func findContainerName(pod *corev1.Pod, containerID string) string {
+// FindContainerName finds the container name by matching container ID in pod status.
+// Returns "" if no container or init container status matches.
+func FindContainerName(pod *corev1.Pod, containerID string) string {
 	// Check regular containers
 	for _, cs := range pod.Status.ContainerStatuses {
 		if matchContainerID(cs.ContainerID, containerID) {
@@ -247,19 +685,32 @@ func findContainerName(pod *corev1.Pod, containerID string) string {
 }
 
 // matchContainerID checks if the container status ID matches the cgroup container ID
-// Container status ID format: "containerd://abc123..." or "cri-o://abc123..."
-// Cgroup container ID format: "abc123..."
+// Container status ID format: "containerd://abc123...", "cri-o://abc123..." or "docker://abc123..."
+// Cgroup container ID format: "abc123..." (docker scopes carry the full 64-char ID,
+// while tools often show the 12-char short form; prefix matching covers both)
 func matchContainerID(statusID, cgroupID string) bool {
-	// Remove runtime prefix (e.g., "containerd://", "cri-o://")
+	// Remove runtime prefix (e.g., "containerd://", "cri-o://", "docker://")
 	if idx := strings.Index(statusID, "://"); idx != -1 {
 		statusID = statusID[idx+3:]
 	}
 
+	// Containers that haven't started yet have no ID; never match them
+	if statusID == "" || cgroupID == "" {
+		return false
+	}
+
 	// Container IDs should match (may be truncated in cgroup)
 	return strings.HasPrefix(statusID, cgroupID) || strings.HasPrefix(cgroupID, statusID)
 }
 
-// RegisterContainerMetricsCollector registers the per-container metrics collector
-func RegisterContainerMetricsCollector(scanner *cgroup.Scanner, podLookup PodLookup, nodeName string) {
-	prometheus.MustRegister(NewContainerMetricsCollector(scanner, podLookup, nodeName))
+// RegisterContainerMetricsCollector registers the per-container metrics collector with the default registry
+func RegisterContainerMetricsCollector(scanner *cgroup.Scanner, podLookup PodLookup, nodeName string, thresholdPercent float64) *ContainerMetricsCollector {
+	return RegisterContainerMetricsCollectorWith(prometheus.DefaultRegisterer, scanner, podLookup, nodeName, thresholdPercent)
+}
+
+// RegisterContainerMetricsCollectorWith registers the per-container metrics collector with reg
+func RegisterContainerMetricsCollectorWith(reg prometheus.Registerer, scanner *cgroup.Scanner, podLookup PodLookup, nodeName string, thresholdPercent float64) *ContainerMetricsCollector {
+	collector := NewContainerMetricsCollector(scanner, podLookup, nodeName, thresholdPercent)
+	reg.MustRegister(collector)
+	return collector
 }
@@ -0,0 +1,160 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rophy/kube-soomkiller/internal/cgroup"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestMatchContainerID(t *testing.T) {
+	fullID := "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	tests := []struct {
+		name     string
+		statusID string
+		cgroupID string
+		expected bool
+	}{
+		{"containerd full", "containerd://" + fullID, fullID, true},
+		{"crio full", "cri-o://" + fullID, fullID, true},
+		{"docker full", "docker://" + fullID, fullID, true},
+		{"docker short cgroup ID", "docker://" + fullID, fullID[:12], true},
+		{"docker short status ID", "docker://" + fullID[:12], fullID, true},
+		{"mismatch", "docker://" + fullID, "fedcba9876543210", false},
+		{"empty status ID", "", fullID, false},
+		{"empty cgroup ID", "docker://" + fullID, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchContainerID(tt.statusID, tt.cgroupID); got != tt.expected {
+				t.Errorf("matchContainerID(%q, %q) = %v, want %v", tt.statusID, tt.cgroupID, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindContainerName(t *testing.T) {
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{Name: "init", ContainerID: "docker://aaa111"},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "pending", ContainerID: ""},
+				{Name: "app", ContainerID: "docker://bbb222"},
+			},
+		},
+	}
+
+	if got := FindContainerName(pod, "bbb222"); got != "app" {
+		t.Errorf("FindContainerName(bbb222) = %q, want app", got)
+	}
+	if got := FindContainerName(pod, "aaa111"); got != "init" {
+		t.Errorf("FindContainerName(aaa111) = %q, want init", got)
+	}
+	if got := FindContainerName(pod, "ccc333"); got != "" {
+		t.Errorf("FindContainerName(ccc333) = %q, want empty", got)
+	}
+}
+
+func TestContainerMetricsCollector_SwapOverLimit(t *testing.T) {
+	root := t.TempDir()
+	podSlice := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podaaaa1111_2222_3333_4444_555566667777.slice"
+	// main swaps 20% of its memory limit, sidecar 5%
+	for id, swap := range map[string]string{"abc123": "20971520", "def456": "5242880"} {
+		dir := filepath.Join(root, podSlice, "cri-containerd-"+id+".scope")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create cgroup dir: %v", err)
+		}
+		files := map[string]string{
+			"memory.swap.current": swap,
+			"memory.swap.max":     "max",
+			"memory.current":      "52428800",
+			"memory.max":          "104857600",
+			"memory.pressure":     "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", name, err)
+			}
+		}
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: types.UID("aaaa1111-2222-3333-4444-555566667777")},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "main", ContainerID: "containerd://abc123"},
+				{Name: "sidecar", ContainerID: "containerd://def456"},
+			},
+		},
+	}
+	collector := NewContainerMetricsCollector(cgroup.NewScanner(root), staticLookup{string(pod.UID): pod}, "test-node", 10.0)
+
+	expected := `
+# HELP soomkiller_container_swap_over_limit 1 if the container alone exceeds the swap kill threshold percent, 0 otherwise
+# TYPE soomkiller_container_swap_over_limit gauge
+soomkiller_container_swap_over_limit{container="main",namespace="default",node="test-node",pod="app"} 1
+soomkiller_container_swap_over_limit{container="sidecar",namespace="default",node="test-node",pod="app"} 0
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "soomkiller_container_swap_over_limit"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestContainerMetricsCollector_Unmatched(t *testing.T) {
+	root := t.TempDir()
+	podSlice := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podaaaa1111_2222_3333_4444_555566667777.slice"
+	for _, id := range []string{"abc123", "fff999"} {
+		if err := os.MkdirAll(filepath.Join(root, podSlice, "cri-containerd-"+id+".scope"), 0755); err != nil {
+			t.Fatalf("Failed to create cgroup dir: %v", err)
+		}
+	}
+
+	// fff999 has no status, as when the cgroup and status IDs disagree
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: types.UID("aaaa1111-2222-3333-4444-555566667777")},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "main", ContainerID: "containerd://abc123"}},
+		},
+	}
+	collector := NewContainerMetricsCollector(cgroup.NewScanner(root), staticLookup{string(pod.UID): pod}, "test-node", 10.0)
+
+	expected := `
+# HELP soomkiller_container_unmatched Container cgroups of known pods currently skipped during collection because no container status matched their ID
+# TYPE soomkiller_container_unmatched gauge
+soomkiller_container_unmatched{node="test-node"} 1
+`
+	// Repeated scrapes report the same container once, not once per scrape
+	for i := 0; i < 2; i++ {
+		if err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "soomkiller_container_unmatched"); err != nil {
+			t.Errorf("scrape %d: %v", i+1, err)
+		}
+	}
+}
+
+func TestRegisterWith_SeparateRegistries(t *testing.T) {
+	scanner := cgroup.NewScanner(t.TempDir())
+
+	// The same metrics registered twice in one process must not conflict
+	for i := 0; i < 2; i++ {
+		reg := prometheus.NewRegistry()
+		NewMetrics("test-node").RegisterWith(reg)
+		RegisterSwapIOCollectorWith(reg, scanner, "test-node")
+		RegisterSwapDeviceCollectorWith(reg, scanner, "test-node")
+		RegisterContainerMetricsCollectorWith(reg, scanner, staticLookup{}, "test-node", 1.0)
+
+		if _, err := reg.Gather(); err != nil {
+			t.Errorf("Gather() error = %v", err)
+		}
+	}
+}
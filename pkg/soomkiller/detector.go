@@ -0,0 +1,140 @@
+// Package soomkiller exposes kube-soomkiller's swap detection for embedding
+// in other node agents. It finds burstable pods using swap from cgroup v2
+// files without killing anything, calling the Kubernetes API, or registering
+// Prometheus metrics.
+//
+// Import it as:
+//
+//	import "github.com/rophy/kube-soomkiller/pkg/soomkiller"
+//
+// Minimal usage:
+//
+//	detector := soomkiller.NewDetector(soomkiller.Config{
+//		CgroupRoot:           "/sys/fs/cgroup",
+//		SwapThresholdPercent: 1,
+//		PodLookup:            myPodCache, // optional, resolves namespace/name
+//	})
+//	candidates, err := detector.Scan(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	for _, cand := range candidates {
+//		if cand.OverThreshold && !cand.Protected {
+//			log.Printf("%s/%s swap %.1f%%", cand.Namespace, cand.Name, cand.SwapPercent)
+//		}
+//	}
+package soomkiller
+
+import (
+	"context"
+	"sort"
+
+	"github.com/rophy/kube-soomkiller/internal/cgroup"
+	"github.com/rophy/kube-soomkiller/internal/controller"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodLookup resolves a pod UID to the pod object, e.g. from an informer cache
+type PodLookup interface {
+	GetPodByUID(uid string) *corev1.Pod
+}
+
+// Config configures a Detector. Zero values select the same defaults as the
+// kube-soomkiller binary.
+type Config struct {
+	CgroupRoot           string    // cgroup v2 mount (default /sys/fs/cgroup)
+	ProcPath             string    // procfs mount for node-level stats (default /proc)
+	RuntimePrefixes      []string  // container scope prefixes (default containerd and CRI-O)
+	KubepodsPath         string    // pod cgroup parent relative to CgroupRoot (default kubepods.slice)
+	ExcludePaths         []string  // cgroup path substrings below KubepodsPath to skip (nil: system.slice, init.scope)
+	UnlimitedBasis       string    // swap percent basis for unlimited containers: "sentinel" (default) or "node-memory"
+	SwapThresholdPercent float64   // candidates with swap percent above this are OverThreshold
+	ProtectedNamespaces  []string  // candidates in these namespaces are Protected
+	PodLookup            PodLookup // optional; without it Namespace, Name and Protected are left empty
+}
+
+// PodCandidate is a burstable pod using swap
+type PodCandidate struct {
+	UID           string
+	Namespace     string  // empty if PodLookup is unset or doesn't know the pod
+	Name          string  // empty if PodLookup is unset or doesn't know the pod
+	SwapBytes     int64   // total swap across containers
+	SwapPercent   float64 // max swap percent across containers
+	PSIFullAvg10  float64 // max memory.pressure full avg10 across containers
+	OverThreshold bool    // SwapPercent > Config.SwapThresholdPercent
+	Protected     bool    // namespace is in Config.ProtectedNamespaces
+}
+
+// Detector scans cgroups for pods using swap. It is safe for sequential use;
+// concurrent Scan calls are independent reads of the cgroup filesystem.
+type Detector struct {
+	config    Config
+	scanner   *cgroup.Scanner
+	protected map[string]bool
+}
+
+// NewDetector creates a Detector
+func NewDetector(config Config) *Detector {
+	root := config.CgroupRoot
+	if root == "" {
+		root = "/sys/fs/cgroup"
+	}
+
+	protected := make(map[string]bool)
+	for _, ns := range config.ProtectedNamespaces {
+		protected[ns] = true
+	}
+
+	return &Detector{
+		config: config,
+		scanner: cgroup.NewScannerWithOptions(root, cgroup.Options{
+			RuntimePrefixes: config.RuntimePrefixes,
+			ProcPath:        config.ProcPath,
+			UnlimitedBasis:  config.UnlimitedBasis,
+			KubepodsPath:    config.KubepodsPath,
+			ExcludePaths:    config.ExcludePaths,
+		}),
+		protected: protected,
+	}
+}
+
+// Scan returns every burstable pod using swap, sorted by swap percent
+// descending (ties by UID). It stops with ctx.Err() once ctx is done.
+func (d *Detector) Scan(ctx context.Context) ([]PodCandidate, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result, err := controller.ScanSwapUsage(ctx, d.scanner, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]PodCandidate, 0, len(result.Candidates))
+	for _, found := range result.Candidates {
+		cand := PodCandidate{
+			UID:           found.UID,
+			SwapBytes:     found.SwapBytes,
+			SwapPercent:   found.SwapPercent,
+			PSIFullAvg10:  found.PSIFullAvg10,
+			OverThreshold: found.SwapPercent > d.config.SwapThresholdPercent,
+		}
+		if d.config.PodLookup != nil {
+			if pod := d.config.PodLookup.GetPodByUID(found.UID); pod != nil {
+				cand.Namespace = pod.Namespace
+				cand.Name = pod.Name
+				cand.Protected = d.protected[pod.Namespace]
+			}
+		}
+		candidates = append(candidates, cand)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].SwapPercent != candidates[j].SwapPercent {
+			return candidates[i].SwapPercent > candidates[j].SwapPercent
+		}
+		return candidates[i].UID < candidates[j].UID
+	})
+
+	return candidates, nil
+}
@@ -0,0 +1,86 @@
+package soomkiller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// staticLookup is a PodLookup backed by a map of UID to pod
+type staticLookup map[string]*corev1.Pod
+
+func (l staticLookup) GetPodByUID(uid string) *corev1.Pod {
+	return l[uid]
+}
+
+func createFakeCgroup(t *testing.T, cgroupRoot, cgroupPath string, swapBytes, memoryMax int64) {
+	t.Helper()
+	fullPath := filepath.Join(cgroupRoot, cgroupPath)
+	if err := os.MkdirAll(fullPath, 0755); err != nil {
+		t.Fatalf("Failed to create cgroup dir: %v", err)
+	}
+
+	files := map[string]string{
+		"memory.swap.current": fmt.Sprintf("%d", swapBytes),
+		"memory.swap.max":     "max",
+		"memory.current":      "268435456",
+		"memory.max":          fmt.Sprintf("%d", memoryMax),
+		"memory.pressure":     "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(fullPath, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestDetectorScan(t *testing.T) {
+	root := t.TempDir()
+	slice := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod"
+	// app swaps 20% of its limit, system 0.5%; the besteffort pod is never a candidate
+	createFakeCgroup(t, root, slice+"aaaa1111_2222_3333_4444_555566667777.slice/cri-containerd-abc.scope", 100<<20, 500<<20)
+	createFakeCgroup(t, root, slice+"bbbb1111_2222_3333_4444_555566667777.slice/cri-containerd-def.scope", 1<<20, 200<<20)
+	createFakeCgroup(t, root, "kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-podcccc1111_2222_3333_4444_555566667777.slice/cri-containerd-fff.scope", 100<<20, 500<<20)
+
+	lookup := staticLookup{
+		"aaaa1111-2222-3333-4444-555566667777": {ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: types.UID("aaaa1111-2222-3333-4444-555566667777")}},
+		"bbbb1111-2222-3333-4444-555566667777": {ObjectMeta: metav1.ObjectMeta{Name: "system", Namespace: "kube-system", UID: types.UID("bbbb1111-2222-3333-4444-555566667777")}},
+	}
+	detector := NewDetector(Config{
+		CgroupRoot:           root,
+		SwapThresholdPercent: 1,
+		ProtectedNamespaces:  []string{"kube-system"},
+		PodLookup:            lookup,
+	})
+
+	candidates, err := detector.Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("Scan() returned %d candidates, want 2", len(candidates))
+	}
+
+	app, system := candidates[0], candidates[1]
+	if app.Name != "app" || app.SwapPercent != 20 || !app.OverThreshold || app.Protected {
+		t.Errorf("candidates[0] = %+v, want app at 20%%, over threshold, unprotected", app)
+	}
+	if system.Name != "system" || system.OverThreshold || !system.Protected {
+		t.Errorf("candidates[1] = %+v, want system below threshold, protected", system)
+	}
+}
+
+func TestDetectorScan_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := NewDetector(Config{CgroupRoot: t.TempDir()}).Scan(ctx); err != context.Canceled {
+		t.Errorf("Scan() error = %v, want context.Canceled", err)
+	}
+}